@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	database_postgres "github.com/teyz/go-svc-template/internal/database/postgres"
+	service_v1 "github.com/teyz/go-svc-template/internal/service/v1"
+	pkg_redis "github.com/teyz/go-svc-template/pkg/cache/redis"
+	pkg_postgres "github.com/teyz/go-svc-template/pkg/database/postgres"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
+)
+
+const apiKeysUsage = "usage: api-keys create -name name [-scopes scope,...] [-expires-in duration] [-tenant tenant] | list [-tenant tenant] | revoke [-tenant tenant] id"
+
+func runAPIKeys(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New(apiKeysUsage)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	databaseConnection, err := pkg_postgres.NewDatabaseConnection(ctx, &cfg.PostgresConfig)
+	if err != nil {
+		return err
+	}
+	defer databaseConnection.Close()
+
+	// the cache is needed for revoked keys to stop being accepted right away
+	cacheConnection := pkg_redis.GetConnection(ctx, &cfg.RedisConfig)
+	defer cacheConnection.Close()
+
+	service, err := service_v1.NewExampleStoreService(ctx, database_postgres.NewClient(ctx, databaseConnection, nil), pkg_redis.NewRedisCache(ctx, cacheConnection))
+	if err != nil {
+		return err
+	}
+
+	// keys are bound to a tenant, and managed within it
+	flags := flag.NewFlagSet("api-keys "+args[0], flag.ContinueOnError)
+	tenant := flags.String("tenant", pkg_tenant.Default, "tenant the keys are bound to")
+
+	switch args[0] {
+	case "create":
+		name := flags.String("name", "", "name of the caller owning the key")
+		scopes := flags.String("scopes", "", "comma separated scopes granted to the key")
+		expiresIn := flags.Duration("expires-in", 0, "validity of the key, it never expires when 0")
+		if err := parseAPIKeysFlags(flags, args[1:], tenant); err != nil {
+			return err
+		}
+		if *name == "" {
+			return errors.New(apiKeysUsage)
+		}
+		ctx = pkg_tenant.WithTenant(ctx, *tenant)
+
+		var expiresAt *time.Time
+		if *expiresIn > 0 {
+			at := time.Now().Add(*expiresIn)
+			expiresAt = &at
+		}
+
+		apiKey, key, err := service.CreateAPIKey(ctx, *name, strings.FieldsFunc(*scopes, func(r rune) bool { return r == ',' }), expiresAt)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("id:  %s\nkey: %s\n\nthe key is only shown once, store it safely\n", apiKey.ID, key)
+
+		return nil
+	case "list":
+		if err := parseAPIKeysFlags(flags, args[1:], tenant); err != nil {
+			return err
+		}
+		ctx = pkg_tenant.WithTenant(ctx, *tenant)
+
+		apiKeys, err := service.FetchAPIKeys(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tEXPIRES\tLAST USED\tREVOKED")
+		for _, apiKey := range apiKeys {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				apiKey.ID, apiKey.Name, apiKey.Prefix, strings.Join(apiKey.Scopes, ","),
+				formatTime(apiKey.ExpiresAt), formatTime(apiKey.LastUsedAt), formatTime(apiKey.RevokedAt))
+		}
+
+		return w.Flush()
+	case "revoke":
+		if err := parseAPIKeysFlags(flags, args[1:], tenant); err != nil {
+			return err
+		}
+		if flags.NArg() != 1 {
+			return errors.New(apiKeysUsage)
+		}
+		ctx = pkg_tenant.WithTenant(ctx, *tenant)
+
+		if err := service.RevokeAPIKey(ctx, flags.Arg(0)); err != nil {
+			return err
+		}
+
+		fmt.Printf("api key %s revoked\n", flags.Arg(0))
+
+		return nil
+	default:
+		return errors.New(apiKeysUsage)
+	}
+}
+
+func parseAPIKeysFlags(flags *flag.FlagSet, args []string, tenant *string) error {
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if !pkg_tenant.IsValid(*tenant) {
+		return fmt.Errorf("invalid tenant: %q", *tenant)
+	}
+
+	return nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pkg_config "github.com/teyz/go-svc-template/pkg/config"
+)
+
+const configUsage = "usage: config print|validate"
+
+func runConfig(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errors.New(configUsage)
+	}
+
+	switch args[0] {
+	case "print":
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range pkg_config.Redacted(cfg) {
+			fmt.Println(entry)
+		}
+
+		return nil
+	case "validate":
+		if _, err := loadConfig(); err != nil {
+			fmt.Println(err)
+			return errors.New("configuration is invalid")
+		}
+
+		fmt.Println("configuration is valid")
+
+		return nil
+	default:
+		return errors.New(configUsage)
+	}
+}
@@ -2,79 +2,93 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
 	"github.com/teyz/go-svc-template/internal/config"
-	database_postgres "github.com/teyz/go-svc-template/internal/database/postgres"
-	handlers_http "github.com/teyz/go-svc-template/internal/handlers/http"
-	service_v1 "github.com/teyz/go-svc-template/internal/service/v1"
-	pkg_redis "github.com/teyz/go-svc-template/pkg/cache/redis"
 	pkg_config "github.com/teyz/go-svc-template/pkg/config"
-	pkg_postgres "github.com/teyz/go-svc-template/pkg/database/postgres"
 )
 
-func main() {
-	ctx, cancel := context.WithCancel(context.Background())
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGTERM)
+// configFile is the path given with the global --config flag.
+var configFile string
 
-	cfg := &config.Config{}
-	err := pkg_config.ParseConfig(cfg)
-	if err != nil {
-		log.Fatal().Err(err).
-			Msg("main: unable to parse config")
+type command struct {
+	name        string
+	description string
+	run         func(ctx context.Context, args []string) error
+}
+
+func commands() []command {
+	return []command{
+		{name: "serve", description: "start the HTTP server (default)", run: runServe},
+		{name: "migrate", description: "run database migrations: up|down|status|redo|version", run: runMigrate},
+		{name: "seed", description: "insert sample examples in the database", run: runSeed},
+		{name: "api-keys", description: "manage API keys: create|list|revoke", run: runAPIKeys},
+		{name: "config", description: "inspect the configuration: print|validate", run: runConfig},
+		{name: "version", description: "print build information", run: runVersion},
 	}
+}
 
+func main() {
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
 
-	cacheConnection := pkg_redis.GetConnection(ctx, &cfg.RedisConfig)
-	cacheRedis := pkg_redis.NewRedisCache(ctx, cacheConnection)
+	flags := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	flags.StringVar(&configFile, "config", "", "path of the YAML or TOML config file, defaults to CONFIG_FILE")
+	flags.Usage = usage
+	flags.Parse(os.Args[1:])
 
-	databaseConnection, err := pkg_postgres.NewDatabaseConnection(ctx, &cfg.PostgresConfig)
-	if err != nil {
-		log.Fatal().Err(err).
-			Msg("main: unable to create database connection")
+	// serve stays the default so that existing images keep starting the server
+	name, args := "serve", flags.Args()
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
 	}
-	databaseClient := database_postgres.NewClient(ctx, databaseConnection)
 
-	exampleStoreService, err := service_v1.NewExampleStoreService(ctx, databaseClient, cacheRedis)
-	if err != nil {
-		log.Fatal().Err(err).
-			Msg("main: unable to create example store service")
-	}
+	for _, cmd := range commands() {
+		if cmd.name != name {
+			continue
+		}
 
-	// create http server
-	httpServer, err := handlers_http.NewServer(ctx, cfg.HTTPServerConfig, exampleStoreService)
-	if err != nil {
-		log.Fatal().Err(err).
-			Msg("main: unable to create http server")
+		if err := cmd.run(context.Background(), args); err != nil {
+			log.Fatal().Err(err).
+				Msgf("main: unable to run %s command", name)
+		}
+
+		os.Exit(0)
 	}
 
-	// setup http server
-	if err := httpServer.Setup(ctx); err != nil {
-		log.Fatal().Err(err).
-			Msg("main: unable to setup http server")
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s [--config file] <command> [arguments]\n\ncommands:\n", os.Args[0])
+	for _, cmd := range commands() {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.description)
 	}
+}
 
-	// start http server
-	if err := httpServer.Start(ctx); err != nil {
-		log.Fatal().Err(err).
-			Msg("main: unable to start http server")
+func setLogLevel(level string) {
+	parsed, err := zerolog.ParseLevel(level)
+	if err != nil {
+		log.Error().Err(err).
+			Str("level", level).
+			Msg("main: invalid log level")
+		return
 	}
 
-	<-sigs
-	cancel()
+	zerolog.SetGlobalLevel(parsed)
+}
 
-	// stop http server
-	if err := httpServer.Stop(ctx); err != nil {
-		log.Fatal().Err(err).
-			Msg("main: unable to stop http server")
+// loadConfig builds the service configuration shared by every command.
+func loadConfig() (*config.Config, error) {
+	cfg := &config.Config{}
+	if err := pkg_config.ParseConfig(cfg, pkg_config.WithConfigFile(configFile)); err != nil {
+		return nil, err
 	}
 
-	os.Exit(0)
+	return cfg, nil
 }
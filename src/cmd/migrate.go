@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+
+	database_migrations "github.com/teyz/go-svc-template/internal/database/migrations"
+	pkg_postgres "github.com/teyz/go-svc-template/pkg/database/postgres"
+)
+
+const migrateUsage = "usage: migrate up|down|status|redo|version"
+
+func runMigrate(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	databaseConnection, err := pkg_postgres.NewDatabaseConnection(ctx, &cfg.PostgresConfig)
+	if err != nil {
+		return err
+	}
+	defer databaseConnection.Close()
+
+	return migrate(ctx, databaseConnection, args[0])
+}
+
+func migrate(ctx context.Context, db *sqlx.DB, action string) error {
+	migrator, err := pkg_postgres.NewMigrator(db, database_migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		return migrator.Down(ctx)
+	case "redo":
+		return migrator.Redo(ctx)
+	case "status":
+		return migrator.Status(ctx)
+	case "version":
+		version, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+
+		log.Info().
+			Int64("version", version).
+			Msg("main: database version")
+
+		return nil
+	default:
+		return errors.New(migrateUsage)
+	}
+}
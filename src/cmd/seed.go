@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	database_postgres "github.com/teyz/go-svc-template/internal/database/postgres"
+	pkg_postgres "github.com/teyz/go-svc-template/pkg/database/postgres"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
+)
+
+func runSeed(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	count := flags.Int("count", 10, "number of examples to create")
+	tenant := flags.String("tenant", pkg_tenant.Default, "tenant owning the examples")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if !pkg_tenant.IsValid(*tenant) {
+		return fmt.Errorf("invalid tenant: %q", *tenant)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	databaseConnection, err := pkg_postgres.NewDatabaseConnection(ctx, &cfg.PostgresConfig)
+	if err != nil {
+		return err
+	}
+	defer databaseConnection.Close()
+
+	databaseClient := database_postgres.NewClient(ctx, databaseConnection, nil)
+	ctx = pkg_tenant.WithTenant(ctx, *tenant)
+
+	for i := 1; i <= *count; i++ {
+		if _, err := databaseClient.CreateExample(ctx, fmt.Sprintf("seeded example #%d", i), cfg.ExampleSearchLanguage); err != nil {
+			return err
+		}
+	}
+
+	log.Info().
+		Int("count", *count).
+		Str("tenant", *tenant).
+		Msg("main: database seeded")
+
+	return nil
+}
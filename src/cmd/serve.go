@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/teyz/go-svc-template/internal/config"
+	database_postgres "github.com/teyz/go-svc-template/internal/database/postgres"
+	handlers_http "github.com/teyz/go-svc-template/internal/handlers/http"
+	"github.com/teyz/go-svc-template/internal/jobs"
+	service_v1 "github.com/teyz/go-svc-template/internal/service/v1"
+	pkg_auth "github.com/teyz/go-svc-template/pkg/auth"
+	pkg_redis "github.com/teyz/go-svc-template/pkg/cache/redis"
+	pkg_config "github.com/teyz/go-svc-template/pkg/config"
+	pkg_postgres "github.com/teyz/go-svc-template/pkg/database/postgres"
+	pkg_featureflags "github.com/teyz/go-svc-template/pkg/featureflags"
+	pkg_ratelimit "github.com/teyz/go-svc-template/pkg/ratelimit"
+)
+
+func runServe(ctx context.Context, args []string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	setLogLevel(cfg.ServiceConfig.LogLevel)
+
+	configWatcher := pkg_config.NewWatcher(cfg, pkg_config.WithConfigFile(configFile))
+
+	cacheConnection := pkg_redis.GetConnection(ctx, &cfg.RedisConfig)
+	cacheRedis := pkg_redis.NewRedisCache(ctx, cacheConnection)
+
+	databaseConnection, err := pkg_postgres.NewDatabaseConnection(ctx, &cfg.PostgresConfig)
+	if err != nil {
+		return err
+	}
+
+	if cfg.PostgresConfig.AutoMigrate {
+		if err := migrate(ctx, databaseConnection, "up"); err != nil {
+			return err
+		}
+	}
+
+	databaseReplicas, err := pkg_postgres.NewReplicaSet(ctx, &cfg.PostgresConfig)
+	if err != nil {
+		return err
+	}
+	databaseClient := database_postgres.NewClient(ctx, databaseConnection, databaseReplicas)
+
+	exampleStoreService, err := service_v1.NewExampleStoreService(ctx, databaseClient, cacheRedis)
+	if err != nil {
+		return err
+	}
+	exampleStoreService.SetCacheDuration(cfg.ExampleCacheDuration)
+	if len(cfg.PostgresConfig.ReplicaDSNs) > 0 {
+		exampleStoreService.SetReplicaLag(cfg.PostgresConfig.ReplicaMaxLag)
+	}
+	exampleStoreService.SetSearchLanguage(cfg.ExampleSearchLanguage)
+
+	featureFlagsProvider, err := pkg_featureflags.NewProvider(&cfg.FeatureFlagsConfig, cacheRedis)
+	if err != nil {
+		return err
+	}
+	featureFlags := pkg_featureflags.NewClient(featureFlagsProvider, cfg.ServiceConfig.ServiceName, cfg.ServiceConfig.Environment)
+
+	rateLimiter := pkg_ratelimit.NewLimiter(&cfg.RateLimitConfig, cacheRedis)
+
+	purgeExamples := jobs.NewPurgeExamples(exampleStoreService, cfg.ExamplePurgeInterval, cfg.ExamplePurgeRetention)
+	go purgeExamples.Run(ctx)
+
+	importExamples := jobs.NewImportExamples(exampleStoreService, cfg.ExampleImportInterval)
+	go importExamples.Run(ctx)
+
+	// apply configuration changes at runtime
+	configWatcher.Subscribe(func(old, new *config.Config) {
+		if old.ServiceConfig.LogLevel != new.ServiceConfig.LogLevel {
+			setLogLevel(new.ServiceConfig.LogLevel)
+		}
+		if old.ExampleCacheDuration != new.ExampleCacheDuration {
+			exampleStoreService.SetCacheDuration(new.ExampleCacheDuration)
+		}
+		if old.ExampleSearchLanguage != new.ExampleSearchLanguage {
+			exampleStoreService.SetSearchLanguage(new.ExampleSearchLanguage)
+		}
+		if old.ExamplePurgeRetention != new.ExamplePurgeRetention {
+			purgeExamples.SetRetention(new.ExamplePurgeRetention)
+		}
+		if provider, ok := featureFlagsProvider.(*pkg_featureflags.MemoryProvider); ok && new.FeatureFlagsConfig.Provider == pkg_featureflags.ProviderConfig {
+			provider.SetFlags(new.FeatureFlagsConfig.Flags)
+		}
+		rateLimiter.SetConfig(&new.RateLimitConfig)
+	})
+	go func() {
+		if err := configWatcher.Watch(ctx); err != nil {
+			log.Error().Err(err).
+				Msg("main: unable to watch configuration")
+		}
+	}()
+
+	// create http server
+	httpServer, err := handlers_http.NewServer(ctx, cfg.HTTPServerConfig, exampleStoreService, featureFlags, pkg_auth.NewAuthenticators(&cfg.AuthConfig, exampleStoreService), rateLimiter, cfg.TenancyConfig)
+	if err != nil {
+		return err
+	}
+
+	// setup http server
+	if err := httpServer.Setup(ctx); err != nil {
+		return err
+	}
+
+	// start http server
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.Start(ctx)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case sig := <-sigs:
+		log.Info().
+			Str("signal", sig.String()).
+			Msg("main: shutting down")
+	}
+
+	cancel()
+
+	// stop http server
+	return httpServer.Stop(context.Background())
+}
@@ -1,16 +1,33 @@
 package config
 
 import (
+	"time"
+
+	pkg_auth "github.com/teyz/go-svc-template/pkg/auth"
 	pkg_redis "github.com/teyz/go-svc-template/pkg/cache/redis"
 	pkg_config "github.com/teyz/go-svc-template/pkg/config"
 	pkg_postgres "github.com/teyz/go-svc-template/pkg/database/postgres"
+	pkg_featureflags "github.com/teyz/go-svc-template/pkg/featureflags"
 	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+	pkg_ratelimit "github.com/teyz/go-svc-template/pkg/ratelimit"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
 )
 
 type Config struct {
 	ServiceConfig pkg_config.ServiceConfig
 
 	HTTPServerConfig pkg_http.HTTPServerConfig
-	PostgresConfig   pkg_postgres.PostgresConfig
-	RedisConfig      pkg_redis.RedisConfig
+	PostgresConfig   pkg_postgres.PostgresConfig `reload:"false"`
+	RedisConfig      pkg_redis.RedisConfig       `reload:"false"`
+
+	AuthConfig         pkg_auth.AuthConfig      `reload:"false"`
+	TenancyConfig      pkg_tenant.TenancyConfig `reload:"false"`
+	FeatureFlagsConfig pkg_featureflags.FeatureFlagsConfig
+	RateLimitConfig    pkg_ratelimit.RateLimitConfig
+
+	ExampleCacheDuration  time.Duration `env:"EXAMPLE_CACHE_DURATION" envDefault:"24h" validate:"min=1s"`
+	ExamplePurgeRetention time.Duration `env:"EXAMPLE_PURGE_RETENTION" envDefault:"720h" validate:"min=0s"`
+	ExamplePurgeInterval  time.Duration `env:"EXAMPLE_PURGE_INTERVAL" envDefault:"1h" validate:"min=1s" reload:"false"`
+	ExampleImportInterval time.Duration `env:"EXAMPLE_IMPORT_INTERVAL" envDefault:"5s" validate:"min=1s" reload:"false"`
+	ExampleSearchLanguage string        `env:"EXAMPLE_SEARCH_LANGUAGE" envDefault:"english" validate:"oneof=simple arabic danish dutch english finnish french german hungarian italian norwegian portuguese romanian russian spanish swedish turkish"`
 }
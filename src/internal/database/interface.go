@@ -2,13 +2,45 @@ package database
 
 import (
 	"context"
+	"time"
 
+	entities_apikey_v1 "github.com/teyz/go-svc-template/internal/entities/apikey/v1"
+	entities_audit_v1 "github.com/teyz/go-svc-template/internal/entities/audit/v1"
 	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	entities_import_v1 "github.com/teyz/go-svc-template/internal/entities/import/v1"
 )
 
 //go:generate mockgen -source interface.go -destination mocks/mock_database.go -package database_mocks
 type Database interface {
-	CreateExample(ctx context.Context, description string) (*entities_example_v1.Example, error)
-	GetExampleByID(ctx context.Context, id string) (*entities_example_v1.Example, error)
-	FetchExamples(ctx context.Context) ([]*entities_example_v1.Example, error)
+	// WithTx runs fn in a transaction, the calls made with its context joining it
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+
+	CreateExample(ctx context.Context, description string, language string) (*entities_example_v1.Example, error)
+	GetExampleByID(ctx context.Context, id string, fields []string) (*entities_example_v1.Example, error)
+	FetchExamples(ctx context.Context, includeDeleted bool, fields []string) ([]*entities_example_v1.Example, error)
+	DeleteExample(ctx context.Context, id string) (*entities_example_v1.Change, error)
+	RestoreExample(ctx context.Context, id string) (*entities_example_v1.Change, error)
+	PurgeExamples(ctx context.Context, deletedBefore time.Time, limit int) ([]*entities_example_v1.PurgedExample, error)
+	SearchExamples(ctx context.Context, query entities_example_v1.SearchQuery) ([]*entities_example_v1.SearchResult, error)
+	CreateExamples(ctx context.Context, descriptions []string, language string) ([]*entities_example_v1.Example, error)
+	UpdateExamples(ctx context.Context, examples []*entities_example_v1.Example, atomic bool) ([]*entities_example_v1.Change, []string, error)
+	DeleteExamples(ctx context.Context, ids []string, atomic bool) ([]*entities_example_v1.Change, []string, error)
+	ExportExamples(ctx context.Context, filter entities_example_v1.ExportFilter, fn func(*entities_example_v1.Example) error) error
+
+	CreateAPIKey(ctx context.Context, name string, prefix string, hash string, scopes []string, expiresAt *time.Time) (*entities_apikey_v1.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, hash string) (*entities_apikey_v1.APIKey, error)
+	FetchAPIKeys(ctx context.Context) ([]*entities_apikey_v1.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) (*entities_apikey_v1.APIKey, *entities_apikey_v1.APIKey, error)
+	TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error
+
+	CreateAuditEntry(ctx context.Context, entry *entities_audit_v1.Entry) error
+	CreateAuditEntries(ctx context.Context, entries []*entities_audit_v1.Entry) error
+	FetchAuditEntries(ctx context.Context, filter entities_audit_v1.Filter) ([]*entities_audit_v1.Entry, error)
+
+	CreateImport(ctx context.Context, imp *entities_import_v1.Import) error
+	GetImportByID(ctx context.Context, id string) (*entities_import_v1.Import, error)
+	FetchImportErrors(ctx context.Context, id string) ([]*entities_import_v1.RowError, error)
+	ClaimImport(ctx context.Context, staleBefore time.Time) (*entities_import_v1.Import, error)
+	ApplyImportChunk(ctx context.Context, id string, start int, end int, descriptions []string, language string, rowErrors []*entities_import_v1.RowError) ([]*entities_example_v1.Example, error)
+	CompleteImport(ctx context.Context, id string, status string, importError string) error
 }
@@ -0,0 +1,26 @@
+-- +goose Up
+
+-- +goose StatementBegin
+CREATE TABLE api_keys (
+    id              VARCHAR(32)     PRIMARY KEY NOT NULL,
+    name            TEXT            NOT NULL,
+    prefix          VARCHAR(16)     NOT NULL,
+    key_hash        CHAR(64)        NOT NULL UNIQUE,
+    scopes          TEXT[]          NOT NULL DEFAULT '{}',
+    expires_at      TIMESTAMP(6),
+    last_used_at    TIMESTAMP(6),
+    revoked_at      TIMESTAMP(6),
+    created_at      TIMESTAMP(6)    NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMP(6)    NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER set_api_keys_updated_at BEFORE UPDATE ON api_keys
+    FOR EACH ROW EXECUTE PROCEDURE set_updated_at_column();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE api_keys;
+-- +goose StatementEnd
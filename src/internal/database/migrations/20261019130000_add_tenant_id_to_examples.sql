@@ -0,0 +1,18 @@
+-- +goose Up
+
+-- +goose StatementBegin
+ALTER TABLE examples ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX examples_tenant_id_idx ON examples (tenant_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX examples_tenant_id_idx;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE examples DROP COLUMN tenant_id;
+-- +goose StatementEnd
@@ -0,0 +1,47 @@
+-- +goose Up
+
+-- +goose StatementBegin
+CREATE TABLE audit_log (
+    id              VARCHAR(32)     PRIMARY KEY NOT NULL,
+    tenant_id       VARCHAR(64)     NOT NULL,
+    actor           TEXT            NOT NULL,
+    action          VARCHAR(32)     NOT NULL,
+    resource_type   VARCHAR(32)     NOT NULL,
+    resource_id     VARCHAR(32)     NOT NULL,
+    before          JSONB,
+    after           JSONB,
+    request_id      TEXT            NOT NULL DEFAULT '',
+    ip              TEXT            NOT NULL DEFAULT '',
+    created_at      TIMESTAMP(6)    NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX audit_log_tenant_id_created_at_idx ON audit_log (tenant_id, created_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX audit_log_resource_idx ON audit_log (resource_type, resource_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION prevent_audit_log_changes() RETURNS TRIGGER AS $$
+  BEGIN
+   RAISE EXCEPTION 'audit_log is append-only';
+  END;
+$$ language 'plpgsql';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE PROCEDURE prevent_audit_log_changes();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE audit_log;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP FUNCTION prevent_audit_log_changes();
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+
+-- +goose StatementBegin
+ALTER TABLE examples ADD COLUMN deleted_at TIMESTAMP(6);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX examples_deleted_at_idx ON examples (deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX examples_deleted_at_idx;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE examples DROP COLUMN deleted_at;
+-- +goose StatementEnd
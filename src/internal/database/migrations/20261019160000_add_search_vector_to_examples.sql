@@ -0,0 +1,26 @@
+-- +goose Up
+
+-- +goose StatementBegin
+ALTER TABLE examples ADD COLUMN search_language REGCONFIG NOT NULL DEFAULT 'english';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE examples ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector(search_language, description)) STORED;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX examples_search_vector_idx ON examples USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX examples_search_vector_idx;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE examples DROP COLUMN search_vector;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE examples DROP COLUMN search_language;
+-- +goose StatementEnd
@@ -0,0 +1,48 @@
+-- +goose Up
+
+-- +goose StatementBegin
+CREATE TABLE imports (
+    id              VARCHAR(32)     PRIMARY KEY NOT NULL,
+    tenant_id       VARCHAR(64)     NOT NULL,
+    actor           TEXT            NOT NULL,
+    format          VARCHAR(16)     NOT NULL,
+    status          VARCHAR(16)     NOT NULL,
+    data            BYTEA           NOT NULL,
+    total_rows      INTEGER         NOT NULL,
+    processed_rows  INTEGER         NOT NULL DEFAULT 0,
+    succeeded_rows  INTEGER         NOT NULL DEFAULT 0,
+    failed_rows     INTEGER         NOT NULL DEFAULT 0,
+    error           TEXT,
+    started_at      TIMESTAMP(6),
+    completed_at    TIMESTAMP(6),
+    created_at      TIMESTAMP(6)    NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMP(6)    NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER set_imports_updated_at BEFORE UPDATE ON imports
+    FOR EACH ROW EXECUTE PROCEDURE set_updated_at_column();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX imports_unfinished_idx ON imports (created_at) WHERE status IN ('pending', 'processing');
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE import_errors (
+    import_id       VARCHAR(32)     NOT NULL REFERENCES imports (id) ON DELETE CASCADE,
+    row_number      INTEGER         NOT NULL,
+    message         TEXT            NOT NULL,
+    PRIMARY KEY (import_id, row_number)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE import_errors;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE imports;
+-- +goose StatementEnd
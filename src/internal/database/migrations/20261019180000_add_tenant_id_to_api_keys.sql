@@ -0,0 +1,18 @@
+-- +goose Up
+
+-- +goose StatementBegin
+ALTER TABLE api_keys ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX api_keys_tenant_id_idx ON api_keys (tenant_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX api_keys_tenant_id_idx;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE api_keys DROP COLUMN tenant_id;
+-- +goose StatementEnd
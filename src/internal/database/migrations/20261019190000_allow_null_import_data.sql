@@ -0,0 +1,18 @@
+-- +goose Up
+
+-- +goose StatementBegin
+ALTER TABLE imports ALTER COLUMN data DROP NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE imports SET data = NULL WHERE status IN ('completed', 'failed');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE imports SET data = '' WHERE data IS NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE imports ALTER COLUMN data SET NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,8 @@
+package database_migrations
+
+import "embed"
+
+// FS holds the SQL migrations so that they ship with the binary.
+//
+//go:embed *.sql
+var FS embed.FS
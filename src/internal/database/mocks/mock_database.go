@@ -1,5 +1,10 @@
 // Code generated by MockGen. DO NOT EDIT.
 // Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source interface.go -destination mocks/mock_database.go -package database_mocks
+//
 
 // Package database_mocks is a generated GoMock package.
 package database_mocks
@@ -7,9 +12,13 @@ package database_mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	entities_apikey_v1 "github.com/teyz/go-svc-template/internal/entities/apikey/v1"
+	entities_audit_v1 "github.com/teyz/go-svc-template/internal/entities/audit/v1"
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	entities_import_v1 "github.com/teyz/go-svc-template/internal/entities/import/v1"
 	gomock "go.uber.org/mock/gomock"
-	v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
 )
 
 // MockDatabase is a mock of Database interface.
@@ -35,47 +44,388 @@ func (m *MockDatabase) EXPECT() *MockDatabaseMockRecorder {
 	return m.recorder
 }
 
+// ApplyImportChunk mocks base method.
+func (m *MockDatabase) ApplyImportChunk(ctx context.Context, id string, start, end int, descriptions []string, language string, rowErrors []*entities_import_v1.RowError) ([]*entities_example_v1.Example, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyImportChunk", ctx, id, start, end, descriptions, language, rowErrors)
+	ret0, _ := ret[0].([]*entities_example_v1.Example)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyImportChunk indicates an expected call of ApplyImportChunk.
+func (mr *MockDatabaseMockRecorder) ApplyImportChunk(ctx, id, start, end, descriptions, language, rowErrors any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyImportChunk", reflect.TypeOf((*MockDatabase)(nil).ApplyImportChunk), ctx, id, start, end, descriptions, language, rowErrors)
+}
+
+// ClaimImport mocks base method.
+func (m *MockDatabase) ClaimImport(ctx context.Context, staleBefore time.Time) (*entities_import_v1.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimImport", ctx, staleBefore)
+	ret0, _ := ret[0].(*entities_import_v1.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimImport indicates an expected call of ClaimImport.
+func (mr *MockDatabaseMockRecorder) ClaimImport(ctx, staleBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimImport", reflect.TypeOf((*MockDatabase)(nil).ClaimImport), ctx, staleBefore)
+}
+
+// CompleteImport mocks base method.
+func (m *MockDatabase) CompleteImport(ctx context.Context, id, status, importError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteImport", ctx, id, status, importError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteImport indicates an expected call of CompleteImport.
+func (mr *MockDatabaseMockRecorder) CompleteImport(ctx, id, status, importError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteImport", reflect.TypeOf((*MockDatabase)(nil).CompleteImport), ctx, id, status, importError)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockDatabase) CreateAPIKey(ctx context.Context, name, prefix, hash string, scopes []string, expiresAt *time.Time) (*entities_apikey_v1.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, name, prefix, hash, scopes, expiresAt)
+	ret0, _ := ret[0].(*entities_apikey_v1.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockDatabaseMockRecorder) CreateAPIKey(ctx, name, prefix, hash, scopes, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockDatabase)(nil).CreateAPIKey), ctx, name, prefix, hash, scopes, expiresAt)
+}
+
+// CreateAuditEntries mocks base method.
+func (m *MockDatabase) CreateAuditEntries(ctx context.Context, entries []*entities_audit_v1.Entry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEntries", ctx, entries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAuditEntries indicates an expected call of CreateAuditEntries.
+func (mr *MockDatabaseMockRecorder) CreateAuditEntries(ctx, entries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEntries", reflect.TypeOf((*MockDatabase)(nil).CreateAuditEntries), ctx, entries)
+}
+
+// CreateAuditEntry mocks base method.
+func (m *MockDatabase) CreateAuditEntry(ctx context.Context, entry *entities_audit_v1.Entry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEntry", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAuditEntry indicates an expected call of CreateAuditEntry.
+func (mr *MockDatabaseMockRecorder) CreateAuditEntry(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEntry", reflect.TypeOf((*MockDatabase)(nil).CreateAuditEntry), ctx, entry)
+}
+
 // CreateExample mocks base method.
-func (m *MockDatabase) CreateExample(ctx context.Context, description string) (*v1.Example, error) {
+func (m *MockDatabase) CreateExample(ctx context.Context, description, language string) (*entities_example_v1.Example, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateExample", ctx, description)
-	ret0, _ := ret[0].(*v1.Example)
+	ret := m.ctrl.Call(m, "CreateExample", ctx, description, language)
+	ret0, _ := ret[0].(*entities_example_v1.Example)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateExample indicates an expected call of CreateExample.
-func (mr *MockDatabaseMockRecorder) CreateExample(ctx, description interface{}) *gomock.Call {
+func (mr *MockDatabaseMockRecorder) CreateExample(ctx, description, language any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExample", reflect.TypeOf((*MockDatabase)(nil).CreateExample), ctx, description, language)
+}
+
+// CreateExamples mocks base method.
+func (m *MockDatabase) CreateExamples(ctx context.Context, descriptions []string, language string) ([]*entities_example_v1.Example, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExamples", ctx, descriptions, language)
+	ret0, _ := ret[0].([]*entities_example_v1.Example)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExamples indicates an expected call of CreateExamples.
+func (mr *MockDatabaseMockRecorder) CreateExamples(ctx, descriptions, language any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExamples", reflect.TypeOf((*MockDatabase)(nil).CreateExamples), ctx, descriptions, language)
+}
+
+// CreateImport mocks base method.
+func (m *MockDatabase) CreateImport(ctx context.Context, imp *entities_import_v1.Import) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImport", ctx, imp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateImport indicates an expected call of CreateImport.
+func (mr *MockDatabaseMockRecorder) CreateImport(ctx, imp any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImport", reflect.TypeOf((*MockDatabase)(nil).CreateImport), ctx, imp)
+}
+
+// DeleteExample mocks base method.
+func (m *MockDatabase) DeleteExample(ctx context.Context, id string) (*entities_example_v1.Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExample", ctx, id)
+	ret0, _ := ret[0].(*entities_example_v1.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExample indicates an expected call of DeleteExample.
+func (mr *MockDatabaseMockRecorder) DeleteExample(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExample", reflect.TypeOf((*MockDatabase)(nil).DeleteExample), ctx, id)
+}
+
+// DeleteExamples mocks base method.
+func (m *MockDatabase) DeleteExamples(ctx context.Context, ids []string, atomic bool) ([]*entities_example_v1.Change, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExamples", ctx, ids, atomic)
+	ret0, _ := ret[0].([]*entities_example_v1.Change)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DeleteExamples indicates an expected call of DeleteExamples.
+func (mr *MockDatabaseMockRecorder) DeleteExamples(ctx, ids, atomic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExamples", reflect.TypeOf((*MockDatabase)(nil).DeleteExamples), ctx, ids, atomic)
+}
+
+// ExportExamples mocks base method.
+func (m *MockDatabase) ExportExamples(ctx context.Context, filter entities_example_v1.ExportFilter, fn func(*entities_example_v1.Example) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportExamples", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportExamples indicates an expected call of ExportExamples.
+func (mr *MockDatabaseMockRecorder) ExportExamples(ctx, filter, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportExamples", reflect.TypeOf((*MockDatabase)(nil).ExportExamples), ctx, filter, fn)
+}
+
+// FetchAPIKeys mocks base method.
+func (m *MockDatabase) FetchAPIKeys(ctx context.Context) ([]*entities_apikey_v1.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchAPIKeys", ctx)
+	ret0, _ := ret[0].([]*entities_apikey_v1.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchAPIKeys indicates an expected call of FetchAPIKeys.
+func (mr *MockDatabaseMockRecorder) FetchAPIKeys(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExample", reflect.TypeOf((*MockDatabase)(nil).CreateExample), ctx, description)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAPIKeys", reflect.TypeOf((*MockDatabase)(nil).FetchAPIKeys), ctx)
+}
+
+// FetchAuditEntries mocks base method.
+func (m *MockDatabase) FetchAuditEntries(ctx context.Context, filter entities_audit_v1.Filter) ([]*entities_audit_v1.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchAuditEntries", ctx, filter)
+	ret0, _ := ret[0].([]*entities_audit_v1.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchAuditEntries indicates an expected call of FetchAuditEntries.
+func (mr *MockDatabaseMockRecorder) FetchAuditEntries(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAuditEntries", reflect.TypeOf((*MockDatabase)(nil).FetchAuditEntries), ctx, filter)
 }
 
 // FetchExamples mocks base method.
-func (m *MockDatabase) FetchExamples(ctx context.Context) ([]*v1.Example, error) {
+func (m *MockDatabase) FetchExamples(ctx context.Context, includeDeleted bool, fields []string) ([]*entities_example_v1.Example, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FetchExamples", ctx)
-	ret0, _ := ret[0].([]*v1.Example)
+	ret := m.ctrl.Call(m, "FetchExamples", ctx, includeDeleted, fields)
+	ret0, _ := ret[0].([]*entities_example_v1.Example)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FetchExamples indicates an expected call of FetchExamples.
-func (mr *MockDatabaseMockRecorder) FetchExamples(ctx interface{}) *gomock.Call {
+func (mr *MockDatabaseMockRecorder) FetchExamples(ctx, includeDeleted, fields any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExamples", reflect.TypeOf((*MockDatabase)(nil).FetchExamples), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchExamples", reflect.TypeOf((*MockDatabase)(nil).FetchExamples), ctx, includeDeleted, fields)
+}
+
+// FetchImportErrors mocks base method.
+func (m *MockDatabase) FetchImportErrors(ctx context.Context, id string) ([]*entities_import_v1.RowError, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchImportErrors", ctx, id)
+	ret0, _ := ret[0].([]*entities_import_v1.RowError)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchImportErrors indicates an expected call of FetchImportErrors.
+func (mr *MockDatabaseMockRecorder) FetchImportErrors(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchImportErrors", reflect.TypeOf((*MockDatabase)(nil).FetchImportErrors), ctx, id)
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockDatabase) GetAPIKeyByHash(ctx context.Context, hash string) (*entities_apikey_v1.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", ctx, hash)
+	ret0, _ := ret[0].(*entities_apikey_v1.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockDatabaseMockRecorder) GetAPIKeyByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockDatabase)(nil).GetAPIKeyByHash), ctx, hash)
 }
 
 // GetExampleByID mocks base method.
-func (m *MockDatabase) GetExampleByID(ctx context.Context, id string) (*v1.Example, error) {
+func (m *MockDatabase) GetExampleByID(ctx context.Context, id string, fields []string) (*entities_example_v1.Example, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetExampleByID", ctx, id)
-	ret0, _ := ret[0].(*v1.Example)
+	ret := m.ctrl.Call(m, "GetExampleByID", ctx, id, fields)
+	ret0, _ := ret[0].(*entities_example_v1.Example)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetExampleByID indicates an expected call of GetExampleByID.
-func (mr *MockDatabaseMockRecorder) GetExampleByID(ctx, id interface{}) *gomock.Call {
+func (mr *MockDatabaseMockRecorder) GetExampleByID(ctx, id, fields any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExampleByID", reflect.TypeOf((*MockDatabase)(nil).GetExampleByID), ctx, id, fields)
+}
+
+// GetImportByID mocks base method.
+func (m *MockDatabase) GetImportByID(ctx context.Context, id string) (*entities_import_v1.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportByID", ctx, id)
+	ret0, _ := ret[0].(*entities_import_v1.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportByID indicates an expected call of GetImportByID.
+func (mr *MockDatabaseMockRecorder) GetImportByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportByID", reflect.TypeOf((*MockDatabase)(nil).GetImportByID), ctx, id)
+}
+
+// PurgeExamples mocks base method.
+func (m *MockDatabase) PurgeExamples(ctx context.Context, deletedBefore time.Time, limit int) ([]*entities_example_v1.PurgedExample, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExamples", ctx, deletedBefore, limit)
+	ret0, _ := ret[0].([]*entities_example_v1.PurgedExample)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExamples indicates an expected call of PurgeExamples.
+func (mr *MockDatabaseMockRecorder) PurgeExamples(ctx, deletedBefore, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExamples", reflect.TypeOf((*MockDatabase)(nil).PurgeExamples), ctx, deletedBefore, limit)
+}
+
+// RestoreExample mocks base method.
+func (m *MockDatabase) RestoreExample(ctx context.Context, id string) (*entities_example_v1.Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreExample", ctx, id)
+	ret0, _ := ret[0].(*entities_example_v1.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreExample indicates an expected call of RestoreExample.
+func (mr *MockDatabaseMockRecorder) RestoreExample(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreExample", reflect.TypeOf((*MockDatabase)(nil).RestoreExample), ctx, id)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockDatabase) RevokeAPIKey(ctx context.Context, id string) (*entities_apikey_v1.APIKey, *entities_apikey_v1.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id)
+	ret0, _ := ret[0].(*entities_apikey_v1.APIKey)
+	ret1, _ := ret[1].(*entities_apikey_v1.APIKey)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockDatabaseMockRecorder) RevokeAPIKey(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockDatabase)(nil).RevokeAPIKey), ctx, id)
+}
+
+// SearchExamples mocks base method.
+func (m *MockDatabase) SearchExamples(ctx context.Context, query entities_example_v1.SearchQuery) ([]*entities_example_v1.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchExamples", ctx, query)
+	ret0, _ := ret[0].([]*entities_example_v1.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchExamples indicates an expected call of SearchExamples.
+func (mr *MockDatabaseMockRecorder) SearchExamples(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchExamples", reflect.TypeOf((*MockDatabase)(nil).SearchExamples), ctx, query)
+}
+
+// TouchAPIKey mocks base method.
+func (m *MockDatabase) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchAPIKey", ctx, id, usedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchAPIKey indicates an expected call of TouchAPIKey.
+func (mr *MockDatabaseMockRecorder) TouchAPIKey(ctx, id, usedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchAPIKey", reflect.TypeOf((*MockDatabase)(nil).TouchAPIKey), ctx, id, usedAt)
+}
+
+// UpdateExamples mocks base method.
+func (m *MockDatabase) UpdateExamples(ctx context.Context, examples []*entities_example_v1.Example, atomic bool) ([]*entities_example_v1.Change, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateExamples", ctx, examples, atomic)
+	ret0, _ := ret[0].([]*entities_example_v1.Change)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateExamples indicates an expected call of UpdateExamples.
+func (mr *MockDatabaseMockRecorder) UpdateExamples(ctx, examples, atomic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateExamples", reflect.TypeOf((*MockDatabase)(nil).UpdateExamples), ctx, examples, atomic)
+}
+
+// WithTx mocks base method.
+func (m *MockDatabase) WithTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockDatabaseMockRecorder) WithTx(ctx, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExampleByID", reflect.TypeOf((*MockDatabase)(nil).GetExampleByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockDatabase)(nil).WithTx), ctx, fn)
 }
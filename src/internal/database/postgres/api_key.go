@@ -0,0 +1,280 @@
+package database_postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+	"github.com/teyz/go-svc-template/pkg/constants"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
+
+	entities_apikey_v1 "github.com/teyz/go-svc-template/internal/entities/apikey/v1"
+)
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanAPIKey scans the columns of an API key, followed by extra ones if any.
+func scanAPIKey(row scanner, apiKey *entities_apikey_v1.APIKey, extra ...interface{}) error {
+	dest := []interface{}{
+		&apiKey.ID,
+		&apiKey.TenantID,
+		&apiKey.Name,
+		&apiKey.Prefix,
+		&apiKey.Hash,
+		pq.Array(&apiKey.Scopes),
+		&apiKey.ExpiresAt,
+		&apiKey.LastUsedAt,
+		&apiKey.RevokedAt,
+		&apiKey.CreatedAt,
+		&apiKey.UpdatedAt,
+	}
+
+	return row.Scan(append(dest, extra...)...)
+}
+
+// CreateAPIKey stores an API key bound to the tenant of the context.
+func (d *dbClient) CreateAPIKey(ctx context.Context, name string, prefix string, hash string, scopes []string, expiresAt *time.Time) (*entities_apikey_v1.APIKey, error) {
+	apiKeyID := constants.GenerateDataPrefixWithULID(constants.APIKey)
+	tenantID := pkg_tenant.FromContext(ctx)
+	now := time.Now()
+
+	if scopes == nil {
+		scopes = make([]string, 0)
+	}
+
+	_, err := d.writer(ctx).ExecContext(ctx,
+		`INSERT INTO
+			api_keys (
+				id,
+				tenant_id,
+				name,
+				prefix,
+				key_hash,
+				scopes,
+				expires_at,
+				created_at,
+				updated_at
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`,
+		apiKeyID, tenantID, name, prefix, hash, pq.Array(scopes), expiresAt, now, now)
+	if err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.CreateAPIKey: failed to create api key: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.CreateAPIKey: failed to create api key: %v", err.Error()))
+	}
+
+	return &entities_apikey_v1.APIKey{
+		ID:        apiKeyID,
+		TenantID:  tenantID,
+		Name:      name,
+		Prefix:    prefix,
+		Hash:      hash,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// GetAPIKeyByHash looks the key up among every tenant, as the tenant of a caller
+// is only known once its key is resolved. It always reads from the primary, a
+// lagging replica could still return a key that was just revoked.
+func (d *dbClient) GetAPIKeyByHash(ctx context.Context, hash string) (*entities_apikey_v1.APIKey, error) {
+	apiKey := &entities_apikey_v1.APIKey{}
+
+	err := scanAPIKey(d.writer(ctx).QueryRowContext(ctx,
+		`SELECT
+			id,
+			tenant_id,
+			name,
+			prefix,
+			key_hash,
+			scopes,
+			expires_at,
+			last_used_at,
+			revoked_at,
+			created_at,
+			updated_at
+		FROM
+			api_keys
+		WHERE
+			key_hash = $1
+		`,
+		hash), apiKey)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Error().Err(err).
+				Msg("database.postgres.dbClient.GetAPIKeyByHash: api key not found")
+			return nil, errors.NewNotFoundError("database.postgres.dbClient.GetAPIKeyByHash: api key not found")
+		}
+
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.GetAPIKeyByHash: failed to get api key by hash: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.GetAPIKeyByHash: failed to get api key by hash: %v", err.Error()))
+	}
+
+	return apiKey, nil
+}
+
+// FetchAPIKeys returns the API keys of the tenant.
+func (d *dbClient) FetchAPIKeys(ctx context.Context) ([]*entities_apikey_v1.APIKey, error) {
+	rows, err := d.reader(ctx).DB.QueryContext(ctx, `
+		SELECT
+			id,
+			tenant_id,
+			name,
+			prefix,
+			key_hash,
+			scopes,
+			expires_at,
+			last_used_at,
+			revoked_at,
+			created_at,
+			updated_at
+		FROM
+			api_keys
+		WHERE
+			tenant_id = $1
+		ORDER BY
+			created_at
+	`, pkg_tenant.FromContext(ctx))
+	if err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.FetchAPIKeys: failed to get api keys: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.FetchAPIKeys: failed to get api keys: %v", err.Error()))
+	}
+	defer rows.Close()
+
+	apiKeys := make([]*entities_apikey_v1.APIKey, 0)
+
+	for rows.Next() {
+		apiKey := &entities_apikey_v1.APIKey{}
+
+		if err := scanAPIKey(rows, apiKey); err != nil {
+			log.Error().Err(err).
+				Msgf("database.postgres.dbClient.FetchAPIKeys: failed to scan api key: %v", err.Error())
+			return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.FetchAPIKeys: failed to scan api key: %v", err.Error()))
+		}
+
+		apiKeys = append(apiKeys, apiKey)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.FetchAPIKeys: failed to read api keys: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.FetchAPIKeys: failed to read api keys: %v", err.Error()))
+	}
+
+	return apiKeys, nil
+}
+
+// RevokeAPIKey revokes an API key of the tenant and returns it before and after
+// the revocation.
+func (d *dbClient) RevokeAPIKey(ctx context.Context, id string) (*entities_apikey_v1.APIKey, *entities_apikey_v1.APIKey, error) {
+	before := &entities_apikey_v1.APIKey{}
+	var revokedAt *time.Time
+	var updatedAt time.Time
+
+	err := scanAPIKey(d.writer(ctx).QueryRowContext(ctx,
+		`UPDATE
+			api_keys
+		SET
+			revoked_at = $2
+		FROM
+			(
+				SELECT
+					id,
+					tenant_id,
+					name,
+					prefix,
+					key_hash,
+					scopes,
+					expires_at,
+					last_used_at,
+					revoked_at,
+					created_at,
+					updated_at
+				FROM
+					api_keys
+				WHERE
+					id = $1 AND tenant_id = $3 AND revoked_at IS NULL
+				FOR UPDATE
+			) AS old
+		WHERE
+			api_keys.id = old.id
+		RETURNING
+			old.id,
+			old.tenant_id,
+			old.name,
+			old.prefix,
+			old.key_hash,
+			old.scopes,
+			old.expires_at,
+			old.last_used_at,
+			old.revoked_at,
+			old.created_at,
+			old.updated_at,
+			api_keys.revoked_at,
+			api_keys.updated_at
+		`,
+		id, time.Now(), pkg_tenant.FromContext(ctx)), before, &revokedAt, &updatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Error().Err(err).
+				Str("id", id).
+				Msgf("database.postgres.dbClient.RevokeAPIKey: api key with id: %s not found", id)
+			return nil, nil, errors.NewNotFoundError(fmt.Sprintf("database.postgres.dbClient.RevokeAPIKey: api key with id: %s not found", id))
+		}
+
+		log.Error().Err(err).
+			Str("id", id).
+			Msgf("database.postgres.dbClient.RevokeAPIKey: failed to revoke api key: %v", err.Error())
+		return nil, nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.RevokeAPIKey: failed to revoke api key: %v", err.Error()))
+	}
+
+	// only the revocation changes
+	after := *before
+	after.RevokedAt = revokedAt
+	after.UpdatedAt = updatedAt
+
+	return before, &after, nil
+}
+
+// TouchAPIKey records the last use of a key. It returns a NotFoundError when the
+// key is unknown or revoked, so that a use racing its revocation is rejected.
+func (d *dbClient) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	result, err := d.writer(ctx).ExecContext(ctx,
+		`UPDATE
+			api_keys
+		SET
+			last_used_at = $2
+		WHERE
+			id = $1 AND revoked_at IS NULL
+		`,
+		id, usedAt)
+	if err != nil {
+		log.Error().Err(err).
+			Str("id", id).
+			Msgf("database.postgres.dbClient.TouchAPIKey: failed to record api key use: %v", err.Error())
+		return errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.TouchAPIKey: failed to record api key use: %v", err.Error()))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).
+			Str("id", id).
+			Msgf("database.postgres.dbClient.TouchAPIKey: failed to record api key use: %v", err.Error())
+		return errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.TouchAPIKey: failed to record api key use: %v", err.Error()))
+	}
+	if affected == 0 {
+		return errors.NewNotFoundError(fmt.Sprintf("database.postgres.dbClient.TouchAPIKey: api key with id: %s not found or revoked", id))
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package database_postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/teyz/go-svc-template/pkg/constants"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
+)
+
+var apiKeyColumns = []string{"id", "tenant_id", "name", "prefix", "key_hash", "scopes", "expires_at", "last_used_at", "revoked_at", "created_at", "updated_at"}
+
+func Test_CreateAPIKey(t *testing.T) {
+	t.Run("ok - create api key", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectExec("INSERT INTO api_keys").WithArgs(sqlmock.AnyArg(), "acme", "batch", "sk_abcdefg", "hash", "{\"examples:read\"}", nil, sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+
+		apiKey, err := sqlxDB.CreateAPIKey(pkg_tenant.WithTenant(context.Background(), "acme"), "batch", "sk_abcdefg", "hash", []string{"examples:read"}, nil)
+		assert.NotNil(t, apiKey)
+		assert.NoError(t, err)
+
+		assert.True(t, constants.APIKey.IsValid(apiKey.ID))
+		assert.Equal(t, "acme", apiKey.TenantID)
+		assert.Equal(t, "batch", apiKey.Name)
+		assert.Equal(t, []string{"examples:read"}, apiKey.Scopes)
+		assert.Nil(t, apiKey.ExpiresAt)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - create api key", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectExec("INSERT INTO api_keys").WillReturnError(errors.NewInternalServerError("error"))
+
+		apiKey, err := sqlxDB.CreateAPIKey(context.Background(), "batch", "sk_abcdefg", "hash", nil, nil)
+		assert.Nil(t, apiKey)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_GetAPIKeyByHash(t *testing.T) {
+	query := "SELECT id, tenant_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at, updated_at FROM api_keys WHERE key_hash = $1"
+
+	t.Run("ok - get api key by hash", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		apiKeyID := constants.GenerateDataPrefixWithULID(constants.APIKey)
+		expiresAt := time.Now().Add(time.Hour)
+
+		rows := sqlmock.NewRows(apiKeyColumns).
+			AddRow(apiKeyID, "default", "batch", "sk_abcdefg", "hash", "{examples:read,examples:write}", expiresAt, nil, nil, time.Now(), time.Now())
+
+		mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(rows)
+
+		apiKey, err := sqlxDB.GetAPIKeyByHash(context.Background(), "hash")
+		assert.NotNil(t, apiKey)
+		assert.NoError(t, err)
+
+		assert.Equal(t, apiKeyID, apiKey.ID)
+		assert.Equal(t, []string{"examples:read", "examples:write"}, apiKey.Scopes)
+		assert.True(t, apiKey.ExpiresAt.Equal(expiresAt))
+		assert.Nil(t, apiKey.LastUsedAt)
+		assert.Nil(t, apiKey.RevokedAt)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - get api key by hash - no rows", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectQuery(query).WithArgs("hash").WillReturnError(sql.ErrNoRows)
+
+		apiKey, err := sqlxDB.GetAPIKeyByHash(context.Background(), "hash")
+		assert.Nil(t, apiKey)
+		assert.True(t, errors.IsNotFoundError(err))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_RevokeAPIKey(t *testing.T) {
+	query := "UPDATE api_keys SET revoked_at = $2 FROM ( SELECT id, tenant_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at, updated_at " +
+		"FROM api_keys WHERE id = $1 AND tenant_id = $3 AND revoked_at IS NULL FOR UPDATE ) AS old WHERE api_keys.id = old.id " +
+		"RETURNING old.id, old.tenant_id, old.name, old.prefix, old.key_hash, old.scopes, old.expires_at, old.last_used_at, old.revoked_at, old.created_at, old.updated_at, api_keys.revoked_at, api_keys.updated_at"
+
+	t.Run("ok - revoke api key", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		apiKeyID := constants.GenerateDataPrefixWithULID(constants.APIKey)
+
+		rows := sqlmock.NewRows(append(apiKeyColumns, "revoked_at", "updated_at")).
+			AddRow(apiKeyID, "default", "batch", "sk_abcdefg", "hash", "{}", nil, nil, nil, time.Now(), time.Now(), time.Now(), time.Now())
+
+		mock.ExpectQuery(query).WithArgs(apiKeyID, AnyTime{}, "default").WillReturnRows(rows)
+
+		before, apiKey, err := sqlxDB.RevokeAPIKey(context.Background(), apiKeyID)
+		assert.NotNil(t, apiKey)
+		assert.NoError(t, err)
+
+		assert.Nil(t, before.RevokedAt)
+		assert.Equal(t, "hash", apiKey.Hash)
+		assert.NotNil(t, apiKey.RevokedAt)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - revoke api key - no rows", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		apiKeyID := constants.GenerateDataPrefixWithULID(constants.APIKey)
+
+		mock.ExpectQuery(query).WithArgs(apiKeyID, AnyTime{}, "default").WillReturnError(sql.ErrNoRows)
+
+		before, apiKey, err := sqlxDB.RevokeAPIKey(context.Background(), apiKeyID)
+		assert.Nil(t, before)
+		assert.Nil(t, apiKey)
+		assert.True(t, errors.IsNotFoundError(err))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_TouchAPIKey(t *testing.T) {
+	query := "UPDATE api_keys SET last_used_at = $2 WHERE id = $1 AND revoked_at IS NULL"
+
+	t.Run("ok - touch api key", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		apiKeyID := constants.GenerateDataPrefixWithULID(constants.APIKey)
+
+		mock.ExpectExec(query).WithArgs(apiKeyID, AnyTime{}).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, sqlxDB.TouchAPIKey(context.Background(), apiKeyID, time.Now()))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - touch revoked api key", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		apiKeyID := constants.GenerateDataPrefixWithULID(constants.APIKey)
+
+		mock.ExpectExec(query).WithArgs(apiKeyID, AnyTime{}).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = sqlxDB.TouchAPIKey(context.Background(), apiKeyID, time.Now())
+		assert.True(t, errors.IsNotFoundError(err))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
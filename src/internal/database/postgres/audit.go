@@ -0,0 +1,189 @@
+package database_postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/teyz/go-svc-template/pkg/constants"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
+
+	entities_audit_v1 "github.com/teyz/go-svc-template/internal/entities/audit/v1"
+)
+
+func (d *dbClient) CreateAuditEntry(ctx context.Context, entry *entities_audit_v1.Entry) error {
+	entry.ID = constants.GenerateDataPrefixWithULID(constants.AuditEntry)
+	entry.CreatedAt = time.Now()
+
+	_, err := d.writer(ctx).ExecContext(ctx,
+		`INSERT INTO
+			audit_log (
+				id,
+				tenant_id,
+				actor,
+				action,
+				resource_type,
+				resource_id,
+				before,
+				after,
+				request_id,
+				ip,
+				created_at
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		`,
+		entry.ID, pkg_tenant.FromContext(ctx), entry.Actor, entry.Action, entry.ResourceType, entry.ResourceID,
+		nullableJSON(entry.Before), nullableJSON(entry.After), entry.RequestID, entry.IP, entry.CreatedAt)
+	if err != nil {
+		log.Error().Err(err).
+			Str("resource_id", entry.ResourceID).
+			Msgf("database.postgres.dbClient.CreateAuditEntry: failed to create audit entry: %v", err.Error())
+		return errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.CreateAuditEntry: failed to create audit entry: %v", err.Error()))
+	}
+
+	return nil
+}
+
+// CreateAuditEntries appends entries to the audit log with a single multi-row INSERT.
+func (d *dbClient) CreateAuditEntries(ctx context.Context, entries []*entities_audit_v1.Entry) error {
+	tenant := pkg_tenant.FromContext(ctx)
+	now := time.Now()
+
+	args := make([]interface{}, 0, len(entries)*11)
+	for _, entry := range entries {
+		entry.ID = constants.GenerateDataPrefixWithULID(constants.AuditEntry)
+		entry.CreatedAt = now
+
+		args = append(args, entry.ID, tenant, entry.Actor, entry.Action, entry.ResourceType, entry.ResourceID,
+			nullableJSON(entry.Before), nullableJSON(entry.After), entry.RequestID, entry.IP, entry.CreatedAt)
+	}
+
+	_, err := d.writer(ctx).ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO
+			audit_log (
+				id,
+				tenant_id,
+				actor,
+				action,
+				resource_type,
+				resource_id,
+				before,
+				after,
+				request_id,
+				ip,
+				created_at
+			)
+			VALUES %s
+	`, valuesPlaceholders(len(entries), 11)), args...)
+	if err != nil {
+		log.Error().Err(err).
+			Int("count", len(entries)).
+			Msgf("database.postgres.dbClient.CreateAuditEntries: failed to create audit entries: %v", err.Error())
+		return errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.CreateAuditEntries: failed to create audit entries: %v", err.Error()))
+	}
+
+	return nil
+}
+
+func (d *dbClient) FetchAuditEntries(ctx context.Context, filter entities_audit_v1.Filter) ([]*entities_audit_v1.Entry, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{pkg_tenant.FromContext(ctx)}
+
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Actor != "" {
+		addCondition("actor = $%d", filter.Actor)
+	}
+	if filter.Action != "" {
+		addCondition("action = $%d", filter.Action)
+	}
+	if filter.ResourceType != "" {
+		addCondition("resource_type = $%d", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		addCondition("resource_id = $%d", filter.ResourceID)
+	}
+	if !filter.Since.IsZero() {
+		addCondition("created_at >= $%d", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		addCondition("created_at < $%d", filter.Until)
+	}
+	args = append(args, filter.Limit)
+
+	rows, err := d.reader(ctx).DB.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			id,
+			actor,
+			action,
+			resource_type,
+			resource_id,
+			before,
+			after,
+			request_id,
+			ip,
+			created_at
+		FROM
+			audit_log
+		WHERE
+			%s
+		ORDER BY
+			created_at DESC
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), len(args)), args...)
+	if err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.FetchAuditEntries: failed to get audit entries: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.FetchAuditEntries: failed to get audit entries: %v", err.Error()))
+	}
+	defer rows.Close()
+
+	entries := make([]*entities_audit_v1.Entry, 0)
+
+	for rows.Next() {
+		entry := &entities_audit_v1.Entry{}
+		var before, after []byte
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Actor,
+			&entry.Action,
+			&entry.ResourceType,
+			&entry.ResourceID,
+			&before,
+			&after,
+			&entry.RequestID,
+			&entry.IP,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			log.Error().Err(err).
+				Msgf("database.postgres.dbClient.FetchAuditEntries: failed to scan audit entry: %v", err.Error())
+			return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.FetchAuditEntries: failed to scan audit entry: %v", err.Error()))
+		}
+		entry.Before, entry.After = before, after
+
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.FetchAuditEntries: failed to read audit entries: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.FetchAuditEntries: failed to read audit entries: %v", err.Error()))
+	}
+
+	return entries, nil
+}
+
+// nullableJSON stores empty documents as NULL rather than as invalid JSON.
+func nullableJSON(document []byte) interface{} {
+	if len(document) == 0 {
+		return nil
+	}
+
+	return string(document)
+}
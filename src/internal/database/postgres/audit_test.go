@@ -0,0 +1,125 @@
+package database_postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/teyz/go-svc-template/pkg/constants"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
+
+	entities_audit_v1 "github.com/teyz/go-svc-template/internal/entities/audit/v1"
+)
+
+func Test_CreateAuditEntry(t *testing.T) {
+	t.Run("ok - create audit entry", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(sqlmock.AnyArg(), "default", "user-1", "create", "example", "exmp_1", nil, `{"id":"exmp_1"}`, "request-1", "10.0.0.1", AnyTime{}).WillReturnResult(sqlmock.NewResult(1, 1))
+
+		entry := &entities_audit_v1.Entry{
+			Actor:        "user-1",
+			Action:       "create",
+			ResourceType: "example",
+			ResourceID:   "exmp_1",
+			After:        []byte(`{"id":"exmp_1"}`),
+			RequestID:    "request-1",
+			IP:           "10.0.0.1",
+		}
+		assert.NoError(t, sqlxDB.CreateAuditEntry(context.Background(), entry))
+
+		assert.True(t, constants.AuditEntry.IsValid(entry.ID))
+		assert.False(t, entry.CreatedAt.IsZero())
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - create audit entry", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectExec("INSERT INTO audit_log").WillReturnError(errors.NewInternalServerError("error"))
+
+		assert.Error(t, sqlxDB.CreateAuditEntry(context.Background(), &entities_audit_v1.Entry{}))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_CreateAuditEntries(t *testing.T) {
+	t.Run("ok - create audit entries", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectExec("INSERT INTO audit_log").WithArgs(
+			sqlmock.AnyArg(), "acme", "user-1", "delete", "example", "exmp_1", nil, `{"id":"exmp_1"}`, "", "", AnyTime{},
+			sqlmock.AnyArg(), "acme", "user-1", "delete", "example", "exmp_2", nil, `{"id":"exmp_2"}`, "", "", AnyTime{},
+		).WillReturnResult(sqlmock.NewResult(0, 2))
+
+		entries := []*entities_audit_v1.Entry{
+			{Actor: "user-1", Action: "delete", ResourceType: "example", ResourceID: "exmp_1", After: []byte(`{"id":"exmp_1"}`)},
+			{Actor: "user-1", Action: "delete", ResourceType: "example", ResourceID: "exmp_2", After: []byte(`{"id":"exmp_2"}`)},
+		}
+		assert.NoError(t, sqlxDB.CreateAuditEntries(pkg_tenant.WithTenant(context.Background(), "acme"), entries))
+
+		for _, entry := range entries {
+			assert.True(t, constants.AuditEntry.IsValid(entry.ID))
+		}
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_FetchAuditEntries(t *testing.T) {
+	t.Run("ok - fetch audit entries with filters", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		since := time.Now().Add(-time.Hour)
+
+		rows := sqlmock.NewRows([]string{"id", "actor", "action", "resource_type", "resource_id", "before", "after", "request_id", "ip", "created_at"}).
+			AddRow("audt_1", "user-1", "create", "example", "exmp_1", nil, []byte(`{"id":"exmp_1"}`), "request-1", "10.0.0.1", time.Now())
+
+		mock.ExpectQuery("SELECT id, actor, action, resource_type, resource_id, before, after, request_id, ip, created_at FROM audit_log WHERE tenant_id = $1 AND actor = $2 AND resource_type = $3 AND created_at >= $4 ORDER BY created_at DESC LIMIT $5").
+			WithArgs("default", "user-1", "example", since, 100).
+			WillReturnRows(rows)
+
+		entries, err := sqlxDB.FetchAuditEntries(context.Background(), entities_audit_v1.Filter{
+			Actor:        "user-1",
+			ResourceType: "example",
+			Since:        since,
+			Limit:        100,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+
+		assert.Nil(t, entries[0].Before)
+		assert.JSONEq(t, `{"id":"exmp_1"}`, string(entries[0].After))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
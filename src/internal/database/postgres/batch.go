@@ -0,0 +1,261 @@
+package database_postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+	"github.com/teyz/go-svc-template/pkg/constants"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
+
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+)
+
+// valuesPlaceholders returns the placeholders of a multi-row VALUES list, such
+// as ($1, $2), ($3, $4) for 2 rows of 2 columns.
+func valuesPlaceholders(rows int, columns int) string {
+	var b strings.Builder
+
+	for row := 0; row < rows; row++ {
+		if row > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("(")
+		for column := 1; column <= columns; column++ {
+			if column > 1 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "$%d", row*columns+column)
+		}
+		b.WriteString(")")
+	}
+
+	return b.String()
+}
+
+// execer runs statements on a connection or within a transaction.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// CreateExamples stores examples with a single multi-row INSERT, so either all
+// of them or none are created.
+func (d *dbClient) CreateExamples(ctx context.Context, descriptions []string, language string) ([]*entities_example_v1.Example, error) {
+	examples, err := insertExamples(ctx, d.writer(ctx), descriptions, language)
+	if err != nil {
+		log.Error().Err(err).
+			Int("count", len(descriptions)).
+			Msgf("database.postgres.dbClient.CreateExamples: failed to create examples: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.CreateExamples: failed to create examples: %v", err.Error()))
+	}
+
+	return examples, nil
+}
+
+func insertExamples(ctx context.Context, db execer, descriptions []string, language string) ([]*entities_example_v1.Example, error) {
+	tenant := pkg_tenant.FromContext(ctx)
+	now := time.Now()
+
+	examples := make([]*entities_example_v1.Example, 0, len(descriptions))
+	args := make([]interface{}, 0, len(descriptions)*6)
+
+	for _, description := range descriptions {
+		example := &entities_example_v1.Example{
+			ID:          constants.GenerateDataPrefixWithULID(constants.Example),
+			Description: description,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+
+		examples = append(examples, example)
+		args = append(args, example.ID, tenant, description, language, now, now)
+	}
+
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO
+			examples (
+				id,
+				tenant_id,
+				description,
+				search_language,
+				created_at,
+				updated_at
+			)
+			VALUES %s
+	`, valuesPlaceholders(len(descriptions), 6)), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return examples, nil
+}
+
+// UpdateExamples updates the description of examples in a transaction and
+// returns the updated ones before and after the update, along with the IDs of
+// those not found. When atomic is set and some are not found, nothing is
+// updated.
+func (d *dbClient) UpdateExamples(ctx context.Context, examples []*entities_example_v1.Example, atomic bool) ([]*entities_example_v1.Change, []string, error) {
+	ids := make([]string, 0, len(examples))
+	descriptions := make([]string, 0, len(examples))
+	for _, example := range examples {
+		ids = append(ids, example.ID)
+		descriptions = append(descriptions, example.Description)
+	}
+
+	return d.writeExamples(ctx, "UpdateExamples", ids, atomic, fmt.Sprintf(`
+		UPDATE
+			examples
+		SET
+			description = items.description,
+			updated_at = NOW()
+		FROM
+			UNNEST($1::TEXT[], $3::TEXT[]) AS items (id, description),
+			(
+				SELECT
+					id,
+					description,
+					created_at,
+					updated_at,
+					deleted_at
+				FROM
+					examples
+				WHERE
+					id = ANY($1) AND tenant_id = $2 AND deleted_at IS NULL
+				FOR UPDATE
+			) AS old
+		WHERE
+			examples.id = items.id AND examples.id = old.id
+		RETURNING
+			%s
+	`, exampleChangeColumns), pq.Array(ids), pkg_tenant.FromContext(ctx), pq.Array(descriptions))
+}
+
+// DeleteExamples soft-deletes examples in a transaction and returns the deleted
+// ones before and after the deletion, along with the IDs of those not found.
+// When atomic is set and some are not found, nothing is deleted.
+func (d *dbClient) DeleteExamples(ctx context.Context, ids []string, atomic bool) ([]*entities_example_v1.Change, []string, error) {
+	return d.writeExamples(ctx, "DeleteExamples", ids, atomic, fmt.Sprintf(`
+		UPDATE
+			examples
+		SET
+			deleted_at = NOW(),
+			updated_at = NOW()
+		FROM
+			(
+				SELECT
+					id,
+					description,
+					created_at,
+					updated_at,
+					deleted_at
+				FROM
+					examples
+				WHERE
+					id = ANY($1) AND tenant_id = $2 AND deleted_at IS NULL
+				FOR UPDATE
+			) AS old
+		WHERE
+			examples.id = old.id
+		RETURNING
+			%s
+	`, exampleChangeColumns), pq.Array(ids), pkg_tenant.FromContext(ctx))
+}
+
+// errExamplesMissing rolls back an atomic write with missing examples.
+var errExamplesMissing = fmt.Errorf("examples are missing")
+
+// writeExamples runs a statement writing the examples of ids and returning
+// their changes, rolling it back when atomic is set and some examples were not
+// written.
+func (d *dbClient) writeExamples(ctx context.Context, method string, ids []string, atomic bool, query string, args ...interface{}) ([]*entities_example_v1.Change, []string, error) {
+	var changes []*entities_example_v1.Change
+	missing := make([]string, 0)
+
+	err := d.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		changes, err = scanExampleChanges(d.writer(ctx).QueryContext(ctx, query, args...))
+		if err != nil {
+			log.Error().Err(err).
+				Msgf("database.postgres.dbClient.%s: failed to write examples: %v", method, err.Error())
+			return errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.%s: failed to write examples: %v", method, err.Error()))
+		}
+
+		written := make(map[string]bool, len(changes))
+		for _, change := range changes {
+			written[change.After.ID] = true
+		}
+
+		for _, id := range ids {
+			if !written[id] {
+				missing = append(missing, id)
+			}
+		}
+
+		if atomic && len(missing) > 0 {
+			return errExamplesMissing
+		}
+
+		return nil
+	})
+	if err == errExamplesMissing {
+		return nil, missing, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return changes, missing, nil
+}
+
+func scanExampleChanges(rows *sql.Rows, err error) ([]*entities_example_v1.Change, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]*entities_example_v1.Change, 0)
+
+	for rows.Next() {
+		change, err := scanExampleChange(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+func scanExamples(rows *sql.Rows, err error) ([]*entities_example_v1.Example, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	examples := make([]*entities_example_v1.Example, 0)
+
+	for rows.Next() {
+		example := &entities_example_v1.Example{}
+
+		err := rows.Scan(
+			&example.ID,
+			&example.Description,
+			&example.CreatedAt,
+			&example.UpdatedAt,
+			&example.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		examples = append(examples, example)
+	}
+
+	return examples, rows.Err()
+}
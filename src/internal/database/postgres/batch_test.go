@@ -0,0 +1,201 @@
+package database_postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	"github.com/teyz/go-svc-template/pkg/constants"
+	"github.com/teyz/go-svc-template/pkg/errors"
+)
+
+func Test_ValuesPlaceholders(t *testing.T) {
+	assert.Equal(t, "($1)", valuesPlaceholders(1, 1))
+	assert.Equal(t, "($1, $2, $3), ($4, $5, $6)", valuesPlaceholders(2, 3))
+}
+
+func Test_CreateExamples(t *testing.T) {
+	t.Run("ok - create examples", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectExec("INSERT INTO examples ( id, tenant_id, description, search_language, created_at, updated_at ) VALUES ($1, $2, $3, $4, $5, $6), ($7, $8, $9, $10, $11, $12)").
+			WithArgs(
+				sqlmock.AnyArg(), "default", "first", "english", AnyTime{}, AnyTime{},
+				sqlmock.AnyArg(), "default", "second", "english", AnyTime{}, AnyTime{},
+			).WillReturnResult(sqlmock.NewResult(0, 2))
+
+		examples, err := sqlxDB.CreateExamples(context.Background(), []string{"first", "second"}, "english")
+		assert.NoError(t, err)
+		assert.Len(t, examples, 2)
+
+		assert.True(t, constants.Example.IsValid(examples[0].ID))
+		assert.Equal(t, "first", examples[0].Description)
+		assert.Equal(t, "second", examples[1].Description)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - create examples", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectExec("INSERT INTO examples").WillReturnError(errors.NewInternalServerError("error"))
+
+		examples, err := sqlxDB.CreateExamples(context.Background(), []string{"first"}, "english")
+		assert.Nil(t, examples)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_UpdateExamples(t *testing.T) {
+	updateQuery := "UPDATE examples SET description = items.description, updated_at = NOW() FROM UNNEST($1::TEXT[], $3::TEXT[]) AS items (id, description), " +
+		"( SELECT id, description, created_at, updated_at, deleted_at FROM examples WHERE id = ANY($1) AND tenant_id = $2 AND deleted_at IS NULL FOR UPDATE ) AS old " +
+		"WHERE examples.id = items.id AND examples.id = old.id RETURNING old.id, old.description, old.created_at, old.updated_at, old.deleted_at, examples.description, examples.created_at, examples.updated_at, examples.deleted_at"
+
+	examples := []*entities_example_v1.Example{
+		{ID: "exmp_1", Description: "first"},
+		{ID: "exmp_2", Description: "second"},
+	}
+
+	t.Run("ok - update examples", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		rows := sqlmock.NewRows(exampleChangeColumnNames).
+			AddRow("exmp_1", "old first", time.Now(), time.Now(), nil, "first", time.Now(), time.Now(), nil).
+			AddRow("exmp_2", "old second", time.Now(), time.Now(), nil, "second", time.Now(), time.Now(), nil)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(updateQuery).
+			WithArgs(pq.Array([]string{"exmp_1", "exmp_2"}), "default", pq.Array([]string{"first", "second"})).WillReturnRows(rows)
+		mock.ExpectCommit()
+
+		updated, missing, err := sqlxDB.UpdateExamples(context.Background(), examples, true)
+		assert.NoError(t, err)
+		assert.Len(t, updated, 2)
+		assert.Equal(t, "old first", updated[0].Before.Description)
+		assert.Equal(t, "first", updated[0].After.Description)
+		assert.Equal(t, "exmp_1", updated[0].After.ID)
+		assert.Empty(t, missing)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("ok - update examples with missing ones in best effort", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		rows := sqlmock.NewRows(exampleChangeColumnNames).
+			AddRow("exmp_2", "old second", time.Now(), time.Now(), nil, "second", time.Now(), time.Now(), nil)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(updateQuery).WillReturnRows(rows)
+		mock.ExpectCommit()
+
+		updated, missing, err := sqlxDB.UpdateExamples(context.Background(), examples, false)
+		assert.NoError(t, err)
+		assert.Len(t, updated, 1)
+		assert.Equal(t, []string{"exmp_1"}, missing)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("ok - roll back atomic update with missing examples", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		rows := sqlmock.NewRows(exampleChangeColumnNames).
+			AddRow("exmp_2", "old second", time.Now(), time.Now(), nil, "second", time.Now(), time.Now(), nil)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(updateQuery).WillReturnRows(rows)
+		mock.ExpectRollback()
+
+		updated, missing, err := sqlxDB.UpdateExamples(context.Background(), examples, true)
+		assert.NoError(t, err)
+		assert.Empty(t, updated)
+		assert.Equal(t, []string{"exmp_1"}, missing)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_DeleteExamples(t *testing.T) {
+	t.Run("ok - delete examples", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		now := time.Now()
+		rows := sqlmock.NewRows(exampleChangeColumnNames).
+			AddRow("exmp_1", "first", now, now, nil, "first", now, now, now)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE examples SET deleted_at = NOW(), updated_at = NOW() FROM ( SELECT id, description, created_at, updated_at, deleted_at FROM examples WHERE id = ANY($1) AND tenant_id = $2 AND deleted_at IS NULL FOR UPDATE ) AS old WHERE examples.id = old.id RETURNING old.id, old.description, old.created_at, old.updated_at, old.deleted_at, examples.description, examples.created_at, examples.updated_at, examples.deleted_at").
+			WithArgs(pq.Array([]string{"exmp_1"}), "default").WillReturnRows(rows)
+		mock.ExpectCommit()
+
+		deleted, missing, err := sqlxDB.DeleteExamples(context.Background(), []string{"exmp_1"}, true)
+		assert.NoError(t, err)
+		assert.Len(t, deleted, 1)
+		assert.Nil(t, deleted[0].Before.DeletedAt)
+		assert.NotNil(t, deleted[0].After.DeletedAt)
+		assert.Empty(t, missing)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - delete examples", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("UPDATE examples").WillReturnError(errors.NewInternalServerError("error"))
+		mock.ExpectRollback()
+
+		deleted, missing, err := sqlxDB.DeleteExamples(context.Background(), []string{"exmp_1"}, false)
+		assert.Nil(t, deleted)
+		assert.Nil(t, missing)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -4,30 +4,35 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/teyz/go-svc-template/pkg/constants"
 	"github.com/teyz/go-svc-template/pkg/errors"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
 
 	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
 )
 
-func (d *dbClient) CreateExample(ctx context.Context, description string) (*entities_example_v1.Example, error) {
+// CreateExample stores an example, its description being indexed for search in language.
+func (d *dbClient) CreateExample(ctx context.Context, description string, language string) (*entities_example_v1.Example, error) {
 	exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
 	now := time.Now()
 
-	_, err := d.connection.DB.ExecContext(ctx,
+	_, err := d.writer(ctx).ExecContext(ctx,
 		`INSERT INTO 
 			examples (
 				id,
+				tenant_id,
 				description,
+				search_language,
 				created_at, 
 				updated_at
 			) 
-			VALUES ($1, $2, $3, $4)
+			VALUES ($1, $2, $3, $4, $5, $6)
 		`,
-		exampleID, description, now, now)
+		exampleID, pkg_tenant.FromContext(ctx), description, language, now, now)
 	if err != nil {
 		log.Error().Err(err).
 			Msgf("database.postgres.dbClient.CreateExample: failed to create example: %v", err.Error())
@@ -42,26 +47,31 @@ func (d *dbClient) CreateExample(ctx context.Context, description string) (*enti
 	}, nil
 }
 
-func (d *dbClient) GetExampleByID(ctx context.Context, id string) (*entities_example_v1.Example, error) {
+// GetExampleByID returns an example of the tenant, reading only the columns of
+// fields, or all of them when fields is empty.
+func (d *dbClient) GetExampleByID(ctx context.Context, id string, fields []string) (*entities_example_v1.Example, error) {
+	if len(fields) == 0 {
+		// soft-deleted examples are not returned, so deleted_at is always null
+		fields = []string{
+			entities_example_v1.FieldID,
+			entities_example_v1.FieldDescription,
+			entities_example_v1.FieldCreatedAt,
+			entities_example_v1.FieldUpdatedAt,
+		}
+	}
+
 	example := &entities_example_v1.Example{}
+	columns, dest := exampleColumns(example, fields)
 
-	err := d.connection.DB.QueryRowContext(ctx,
+	err := d.reader(ctx).DB.QueryRowContext(ctx, fmt.Sprintf(
 		`SELECT
-			id,
-			description,
-			created_at,
-			updated_at
+			%s
 		FROM
 			examples
 		WHERE
-			id = $1
-		`,
-		id).Scan(
-		&example.ID,
-		&example.Description,
-		&example.CreatedAt,
-		&example.UpdatedAt,
-	)
+			id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		`, columns),
+		id, pkg_tenant.FromContext(ctx)).Scan(dest...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Error().Err(err).
@@ -79,16 +89,23 @@ func (d *dbClient) GetExampleByID(ctx context.Context, id string) (*entities_exa
 	return example, nil
 }
 
-func (d *dbClient) FetchExamples(ctx context.Context) ([]*entities_example_v1.Example, error) {
-	rows, err := d.connection.DB.QueryContext(ctx, `
+// FetchExamples returns the examples of the tenant, soft-deleted ones being
+// excluded unless includeDeleted is set. Only the columns of fields are read,
+// or all of them when fields is empty.
+func (d *dbClient) FetchExamples(ctx context.Context, includeDeleted bool, fields []string) ([]*entities_example_v1.Example, error) {
+	if len(fields) == 0 {
+		fields = entities_example_v1.Fields
+	}
+	columns, _ := exampleColumns(&entities_example_v1.Example{}, fields)
+
+	rows, err := d.reader(ctx).DB.QueryContext(ctx, fmt.Sprintf(`
 		SELECT
-			id,
-			description,
-			created_at,
-			updated_at
+			%s
 		FROM
 			examples
-	`)
+		WHERE
+			tenant_id = $1 AND ($2 OR deleted_at IS NULL)
+	`, columns), pkg_tenant.FromContext(ctx), includeDeleted)
 	if err != nil {
 		log.Error().Err(err).
 			Msgf("database.postgres.dbClient.FetchExamples: failed to get examples: %v", err.Error())
@@ -100,14 +117,9 @@ func (d *dbClient) FetchExamples(ctx context.Context) ([]*entities_example_v1.Ex
 
 	for rows.Next() {
 		example := &entities_example_v1.Example{}
+		_, dest := exampleColumns(example, fields)
 
-		err := rows.Scan(
-			&example.ID,
-			&example.Description,
-			&example.CreatedAt,
-			&example.UpdatedAt,
-		)
-		if err != nil {
+		if err := rows.Scan(dest...); err != nil {
 			log.Error().Err(err).
 				Msgf("database.postgres.dbClient.FetchExamples: failed to scan example: %v", err.Error())
 			return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.FetchExamples: failed to scan example: %v", err.Error()))
@@ -115,6 +127,180 @@ func (d *dbClient) FetchExamples(ctx context.Context) ([]*entities_example_v1.Ex
 
 		examples = append(examples, example)
 	}
+	if err := rows.Err(); err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.FetchExamples: failed to read examples: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.FetchExamples: failed to read examples: %v", err.Error()))
+	}
 
 	return examples, nil
 }
+
+// exampleColumns returns the column list of fields along with where to scan
+// each of them in example. Unknown fields are left out.
+func exampleColumns(example *entities_example_v1.Example, fields []string) (string, []interface{}) {
+	columns := make([]string, 0, len(fields))
+	dest := make([]interface{}, 0, len(fields))
+
+	for _, field := range fields {
+		switch field {
+		case entities_example_v1.FieldID:
+			dest = append(dest, &example.ID)
+		case entities_example_v1.FieldDescription:
+			dest = append(dest, &example.Description)
+		case entities_example_v1.FieldCreatedAt:
+			dest = append(dest, &example.CreatedAt)
+		case entities_example_v1.FieldUpdatedAt:
+			dest = append(dest, &example.UpdatedAt)
+		case entities_example_v1.FieldDeletedAt:
+			dest = append(dest, &example.DeletedAt)
+		default:
+			continue
+		}
+		columns = append(columns, field)
+	}
+
+	return strings.Join(columns, ", "), dest
+}
+
+// DeleteExample soft-deletes an example, it can be restored until it is purged.
+func (d *dbClient) DeleteExample(ctx context.Context, id string) (*entities_example_v1.Change, error) {
+	return d.setExampleDeletedAt(ctx, "DeleteExample", id, "deleted_at IS NULL", sql.NullTime{Time: time.Now(), Valid: true})
+}
+
+// RestoreExample restores a soft-deleted example.
+func (d *dbClient) RestoreExample(ctx context.Context, id string) (*entities_example_v1.Change, error) {
+	return d.setExampleDeletedAt(ctx, "RestoreExample", id, "deleted_at IS NOT NULL", sql.NullTime{})
+}
+
+// setExampleDeletedAt returns the example before and after the update, the row
+// being locked to read the state it had right before.
+func (d *dbClient) setExampleDeletedAt(ctx context.Context, method string, id string, condition string, deletedAt sql.NullTime) (*entities_example_v1.Change, error) {
+	change, err := scanExampleChange(d.writer(ctx).QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE
+			examples
+		SET
+			deleted_at = $3,
+			updated_at = NOW()
+		FROM
+			(
+				SELECT
+					id,
+					description,
+					created_at,
+					updated_at,
+					deleted_at
+				FROM
+					examples
+				WHERE
+					id = $1 AND tenant_id = $2 AND %s
+				FOR UPDATE
+			) AS old
+		WHERE
+			examples.id = old.id
+		RETURNING
+			%s
+		`, condition, exampleChangeColumns),
+		id, pkg_tenant.FromContext(ctx), deletedAt))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Error().Err(err).
+				Str("id", id).
+				Msgf("database.postgres.dbClient.%s: example with id: %s not found", method, id)
+			return nil, errors.NewNotFoundError(fmt.Sprintf("database.postgres.dbClient.%s: example with id: %s not found", method, id))
+		}
+
+		log.Error().Err(err).
+			Str("id", id).
+			Msgf("database.postgres.dbClient.%s: failed to update example: %v", method, err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.%s: failed to update example: %v", method, err.Error()))
+	}
+
+	return change, nil
+}
+
+// exampleChangeColumns are returned by the updates of examples joined with
+// their locked rows as old, to scan them with scanExampleChange.
+const exampleChangeColumns = `old.id,
+			old.description,
+			old.created_at,
+			old.updated_at,
+			old.deleted_at,
+			examples.description,
+			examples.created_at,
+			examples.updated_at,
+			examples.deleted_at`
+
+func scanExampleChange(row scanner) (*entities_example_v1.Change, error) {
+	before := &entities_example_v1.Example{}
+	after := &entities_example_v1.Example{}
+
+	err := row.Scan(
+		&before.ID,
+		&before.Description,
+		&before.CreatedAt,
+		&before.UpdatedAt,
+		&before.DeletedAt,
+		&after.Description,
+		&after.CreatedAt,
+		&after.UpdatedAt,
+		&after.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	after.ID = before.ID
+
+	return &entities_example_v1.Change{Before: before, After: after}, nil
+}
+
+// PurgeExamples hard-deletes up to limit examples of every tenant soft-deleted
+// before deletedBefore and returns them. Examples locked by a concurrent purge
+// are skipped, so that several instances can purge at once.
+func (d *dbClient) PurgeExamples(ctx context.Context, deletedBefore time.Time, limit int) ([]*entities_example_v1.PurgedExample, error) {
+	rows, err := d.writer(ctx).QueryContext(ctx, `
+		DELETE FROM
+			examples
+		WHERE
+			id IN (
+				SELECT
+					id
+				FROM
+					examples
+				WHERE
+					deleted_at < $1
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING
+			id,
+			tenant_id
+	`, deletedBefore, limit)
+	if err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.PurgeExamples: failed to purge examples: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.PurgeExamples: failed to purge examples: %v", err.Error()))
+	}
+	defer rows.Close()
+
+	purged := make([]*entities_example_v1.PurgedExample, 0)
+
+	for rows.Next() {
+		example := &entities_example_v1.PurgedExample{}
+
+		if err := rows.Scan(&example.ID, &example.TenantID); err != nil {
+			log.Error().Err(err).
+				Msgf("database.postgres.dbClient.PurgeExamples: failed to scan purged example: %v", err.Error())
+			return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.PurgeExamples: failed to scan purged example: %v", err.Error()))
+		}
+
+		purged = append(purged, example)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.PurgeExamples: failed to read purged examples: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.PurgeExamples: failed to read purged examples: %v", err.Error()))
+	}
+
+	return purged, nil
+}
@@ -10,8 +10,11 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
 	"github.com/teyz/go-svc-template/pkg/constants"
+	pkg_postgres "github.com/teyz/go-svc-template/pkg/database/postgres"
 	"github.com/teyz/go-svc-template/pkg/errors"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
 )
 
 type AnyTime struct{}
@@ -31,9 +34,9 @@ func Test_CreateExample(t *testing.T) {
 			connection: sqlx.NewDb(db, "sqlmock"),
 		}
 
-		mock.ExpectExec("INSERT INTO examples").WithArgs(sqlmock.AnyArg(), "hello world !", sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO examples").WithArgs(sqlmock.AnyArg(), "default", "hello world !", "english", sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
 
-		example, err := sqlxDB.CreateExample(context.Background(), "hello world !")
+		example, err := sqlxDB.CreateExample(context.Background(), "hello world !", "english")
 		assert.NotNil(t, example)
 		assert.NoError(t, err)
 
@@ -55,9 +58,9 @@ func Test_CreateExample(t *testing.T) {
 			connection: sqlx.NewDb(db, "sqlmock"),
 		}
 
-		mock.ExpectExec("INSERT INTO examples").WithArgs(sqlmock.AnyArg(), "hello world !", sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnError(errors.NewInternalServerError("error"))
+		mock.ExpectExec("INSERT INTO examples").WithArgs(sqlmock.AnyArg(), "default", "hello world !", "english", sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnError(errors.NewInternalServerError("error"))
 
-		channel, err := sqlxDB.CreateExample(context.Background(), "hello world !")
+		channel, err := sqlxDB.CreateExample(context.Background(), "hello world !", "english")
 		assert.Nil(t, channel)
 		assert.Error(t, err)
 
@@ -82,9 +85,9 @@ func Test_GetExampleByID(t *testing.T) {
 		rows := sqlmock.NewRows([]string{"id", "description", "created_at", "updated_at"}).
 			AddRow(exampleID, "hello world !", time.Now(), time.Now())
 
-		mock.ExpectQuery("SELECT id, description, created_at, updated_at FROM examples WHERE id = $1").WithArgs(exampleID).WillReturnError(nil).WillReturnRows(rows)
+		mock.ExpectQuery("SELECT id, description, created_at, updated_at FROM examples WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL").WithArgs(exampleID, "default").WillReturnError(nil).WillReturnRows(rows)
 
-		example, err := sqlxDB.GetExampleByID(context.Background(), exampleID)
+		example, err := sqlxDB.GetExampleByID(context.Background(), exampleID, nil)
 		assert.NotNil(t, example)
 		assert.NoError(t, err)
 
@@ -97,6 +100,62 @@ func Test_GetExampleByID(t *testing.T) {
 			t.Errorf("there were unfulfilled expectations: %s", err)
 		}
 	})
+	t.Run("ok - get example by id from replica", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		replicaDB, replicaMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer replicaDB.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+			replicas:   pkg_postgres.NewReplicaSetFromDB(pkg_postgres.ReplicaSelectionRoundRobin, sqlx.NewDb(replicaDB, "sqlmock")),
+		}
+
+		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
+
+		rows := sqlmock.NewRows([]string{"id", "description", "created_at", "updated_at"}).
+			AddRow(exampleID, "hello world !", time.Now(), time.Now())
+
+		replicaMock.ExpectQuery("SELECT id, description, created_at, updated_at FROM examples WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL").WithArgs(exampleID, "default").WillReturnRows(rows)
+
+		example, err := sqlxDB.GetExampleByID(context.Background(), exampleID, nil)
+		assert.NotNil(t, example)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+	})
+	t.Run("ok - get example by id from primary when forced", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		replicaDB, replicaMock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer replicaDB.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+			replicas:   pkg_postgres.NewReplicaSetFromDB(pkg_postgres.ReplicaSelectionRoundRobin, sqlx.NewDb(replicaDB, "sqlmock")),
+		}
+
+		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
+
+		rows := sqlmock.NewRows([]string{"id", "description", "created_at", "updated_at"}).
+			AddRow(exampleID, "hello world !", time.Now(), time.Now())
+
+		mock.ExpectQuery("SELECT id, description, created_at, updated_at FROM examples WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL").WithArgs(exampleID, "default").WillReturnRows(rows)
+
+		example, err := sqlxDB.GetExampleByID(pkg_postgres.WithPrimary(context.Background()), exampleID, nil)
+		assert.NotNil(t, example)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+	})
 	t.Run("nok - get example by id", func(t *testing.T) {
 		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 		assert.NoError(t, err)
@@ -108,9 +167,9 @@ func Test_GetExampleByID(t *testing.T) {
 
 		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
 
-		mock.ExpectQuery("SELECT id, description, created_at, updated_at FROM examples WHERE id = $1").WithArgs(exampleID).WillReturnError(errors.NewInternalServerError("error"))
+		mock.ExpectQuery("SELECT id, description, created_at, updated_at FROM examples WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL").WithArgs(exampleID, "default").WillReturnError(errors.NewInternalServerError("error"))
 
-		example, err := sqlxDB.GetExampleByID(context.Background(), exampleID)
+		example, err := sqlxDB.GetExampleByID(context.Background(), exampleID, nil)
 		assert.Nil(t, example)
 		assert.Error(t, err)
 
@@ -129,9 +188,9 @@ func Test_GetExampleByID(t *testing.T) {
 
 		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
 
-		mock.ExpectQuery("SELECT id, description, created_at, updated_at FROM examples WHERE id = $1").WithArgs(exampleID).WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery("SELECT id, description, created_at, updated_at FROM examples WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL").WithArgs(exampleID, "default").WillReturnError(sql.ErrNoRows)
 
-		channel, err := sqlxDB.GetExampleByID(context.Background(), exampleID)
+		channel, err := sqlxDB.GetExampleByID(context.Background(), exampleID, nil)
 		assert.Nil(t, channel)
 		assert.Error(t, err)
 
@@ -153,12 +212,12 @@ func Test_GetExamples(t *testing.T) {
 
 		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
 
-		rows := sqlmock.NewRows([]string{"id", "description", "created_at", "updated_at"}).
-			AddRow(exampleID, "hello world !", time.Now(), time.Now())
+		rows := sqlmock.NewRows([]string{"id", "description", "created_at", "updated_at", "deleted_at"}).
+			AddRow(exampleID, "hello world !", time.Now(), time.Now(), nil)
 
-		mock.ExpectQuery("SELECT id, description, created_at, updated_at FROM examples").WillReturnError(nil).WillReturnRows(rows)
+		mock.ExpectQuery("SELECT id, description, created_at, updated_at, deleted_at FROM examples WHERE tenant_id = $1 AND ($2 OR deleted_at IS NULL)").WithArgs("default", false).WillReturnError(nil).WillReturnRows(rows)
 
-		examples, err := sqlxDB.FetchExamples(context.Background())
+		examples, err := sqlxDB.FetchExamples(context.Background(), false, nil)
 		assert.NotNil(t, examples)
 		assert.NoError(t, err)
 
@@ -173,6 +232,45 @@ func Test_GetExamples(t *testing.T) {
 			t.Errorf("there were unfulfilled expectations: %s", err)
 		}
 	})
+	t.Run("ok - get examples of tenant including deleted", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		rows := sqlmock.NewRows([]string{"id", "description", "created_at", "updated_at", "deleted_at"})
+
+		mock.ExpectQuery("SELECT id, description, created_at, updated_at, deleted_at FROM examples WHERE tenant_id = $1 AND ($2 OR deleted_at IS NULL)").WithArgs("acme", true).WillReturnRows(rows)
+
+		examples, err := sqlxDB.FetchExamples(pkg_tenant.WithTenant(context.Background(), "acme"), true, nil)
+		assert.Empty(t, examples)
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("ok - get examples with selected fields", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		rows := sqlmock.NewRows([]string{"id", "description"}).
+			AddRow("exmp_1", "hello world !")
+
+		mock.ExpectQuery("SELECT id, description FROM examples WHERE tenant_id = $1 AND ($2 OR deleted_at IS NULL)").WithArgs("default", false).WillReturnRows(rows)
+
+		examples, err := sqlxDB.FetchExamples(context.Background(), false, []string{"id", "description"})
+		assert.NoError(t, err)
+		assert.Equal(t, []*entities_example_v1.Example{{ID: "exmp_1", Description: "hello world !"}}, examples)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 	t.Run("nok - get examples", func(t *testing.T) {
 		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 		assert.NoError(t, err)
@@ -182,9 +280,9 @@ func Test_GetExamples(t *testing.T) {
 			connection: sqlx.NewDb(db, "sqlmock"),
 		}
 
-		mock.ExpectQuery("SELECT id, description, created_at, updated_at FROM examples").WillReturnError(errors.NewInternalServerError("error"))
+		mock.ExpectQuery("SELECT id, description, created_at, updated_at, deleted_at FROM examples WHERE tenant_id = $1 AND ($2 OR deleted_at IS NULL)").WithArgs("default", false).WillReturnError(errors.NewInternalServerError("error"))
 
-		examples, err := sqlxDB.FetchExamples(context.Background())
+		examples, err := sqlxDB.FetchExamples(context.Background(), false, nil)
 		assert.Nil(t, examples)
 		assert.Error(t, err)
 
@@ -201,9 +299,9 @@ func Test_GetExamples(t *testing.T) {
 			connection: sqlx.NewDb(db, "sqlmock"),
 		}
 
-		mock.ExpectQuery("SELECT id, description, created_at, updated_at FROM examples").WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery("SELECT id, description, created_at, updated_at, deleted_at FROM examples WHERE tenant_id = $1 AND ($2 OR deleted_at IS NULL)").WithArgs("default", false).WillReturnError(sql.ErrNoRows)
 
-		examples, err := sqlxDB.FetchExamples(context.Background())
+		examples, err := sqlxDB.FetchExamples(context.Background(), false, nil)
 		assert.Nil(t, examples)
 		assert.Error(t, err)
 
@@ -211,4 +309,178 @@ func Test_GetExamples(t *testing.T) {
 			t.Errorf("there were unfulfilled expectations: %s", err)
 		}
 	})
+	t.Run("nok - get examples - rows interrupted", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		rows := sqlmock.NewRows([]string{"id", "description", "created_at", "updated_at", "deleted_at"}).
+			AddRow("exmp_1", "hello world !", time.Now(), time.Now(), nil).
+			AddRow("exmp_2", "hello world !", time.Now(), time.Now(), nil).
+			RowError(1, errors.NewInternalServerError("connection reset"))
+
+		mock.ExpectQuery("SELECT id, description, created_at, updated_at, deleted_at FROM examples WHERE tenant_id = $1 AND ($2 OR deleted_at IS NULL)").WithArgs("default", false).WillReturnRows(rows)
+
+		examples, err := sqlxDB.FetchExamples(context.Background(), false, nil)
+		assert.Nil(t, examples)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// exampleChangeColumnNames are the columns of an example before and after an update.
+var exampleChangeColumnNames = []string{"id", "description", "created_at", "updated_at", "deleted_at", "description", "created_at", "updated_at", "deleted_at"}
+
+func Test_DeleteExample(t *testing.T) {
+	t.Run("ok - delete example", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
+		now := time.Now()
+
+		rows := sqlmock.NewRows(exampleChangeColumnNames).
+			AddRow(exampleID, "hello world !", now, now, nil, "hello world !", now, now, now)
+
+		mock.ExpectQuery("UPDATE examples SET deleted_at = $3, updated_at = NOW() FROM ( SELECT id, description, created_at, updated_at, deleted_at FROM examples WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL FOR UPDATE ) AS old WHERE examples.id = old.id RETURNING old.id, old.description, old.created_at, old.updated_at, old.deleted_at, examples.description, examples.created_at, examples.updated_at, examples.deleted_at").
+			WithArgs(exampleID, "default", sqlmock.AnyArg()).WillReturnRows(rows)
+
+		change, err := sqlxDB.DeleteExample(context.Background(), exampleID)
+		assert.NotNil(t, change)
+		assert.NoError(t, err)
+
+		assert.Equal(t, exampleID, change.Before.ID)
+		assert.Equal(t, exampleID, change.After.ID)
+		assert.Nil(t, change.Before.DeletedAt)
+		assert.NotNil(t, change.After.DeletedAt)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - delete example already deleted", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
+
+		mock.ExpectQuery("UPDATE examples SET deleted_at = $3, updated_at = NOW() FROM ( SELECT id, description, created_at, updated_at, deleted_at FROM examples WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL FOR UPDATE ) AS old WHERE examples.id = old.id RETURNING old.id, old.description, old.created_at, old.updated_at, old.deleted_at, examples.description, examples.created_at, examples.updated_at, examples.deleted_at").
+			WithArgs(exampleID, "default", sqlmock.AnyArg()).WillReturnError(sql.ErrNoRows)
+
+		change, err := sqlxDB.DeleteExample(context.Background(), exampleID)
+		assert.Nil(t, change)
+		assert.True(t, errors.IsNotFoundError(err))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_RestoreExample(t *testing.T) {
+	t.Run("ok - restore example", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
+		now := time.Now()
+
+		rows := sqlmock.NewRows(exampleChangeColumnNames).
+			AddRow(exampleID, "hello world !", now, now, now, "hello world !", now, now, nil)
+
+		mock.ExpectQuery("UPDATE examples SET deleted_at = $3, updated_at = NOW() FROM ( SELECT id, description, created_at, updated_at, deleted_at FROM examples WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NOT NULL FOR UPDATE ) AS old WHERE examples.id = old.id RETURNING old.id, old.description, old.created_at, old.updated_at, old.deleted_at, examples.description, examples.created_at, examples.updated_at, examples.deleted_at").
+			WithArgs(exampleID, "acme", nil).WillReturnRows(rows)
+
+		change, err := sqlxDB.RestoreExample(pkg_tenant.WithTenant(context.Background(), "acme"), exampleID)
+		assert.NotNil(t, change)
+		assert.NoError(t, err)
+
+		assert.NotNil(t, change.Before.DeletedAt)
+		assert.Nil(t, change.After.DeletedAt)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - restore example not deleted", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
+
+		mock.ExpectQuery("UPDATE examples SET deleted_at = $3, updated_at = NOW() FROM ( SELECT id, description, created_at, updated_at, deleted_at FROM examples WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NOT NULL FOR UPDATE ) AS old WHERE examples.id = old.id RETURNING old.id, old.description, old.created_at, old.updated_at, old.deleted_at, examples.description, examples.created_at, examples.updated_at, examples.deleted_at").
+			WithArgs(exampleID, "default", nil).WillReturnError(sql.ErrNoRows)
+
+		change, err := sqlxDB.RestoreExample(context.Background(), exampleID)
+		assert.Nil(t, change)
+		assert.True(t, errors.IsNotFoundError(err))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_PurgeExamples(t *testing.T) {
+	t.Run("ok - purge examples", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		deletedBefore := time.Now().Add(-time.Hour)
+
+		rows := sqlmock.NewRows([]string{"id", "tenant_id"}).
+			AddRow("exmp_1", "default").
+			AddRow("exmp_2", "acme")
+
+		mock.ExpectQuery("DELETE FROM examples WHERE id IN ( SELECT id FROM examples WHERE deleted_at < $1 LIMIT $2 FOR UPDATE SKIP LOCKED ) RETURNING id, tenant_id").WithArgs(deletedBefore, 100).WillReturnRows(rows)
+
+		purged, err := sqlxDB.PurgeExamples(context.Background(), deletedBefore, 100)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []*entities_example_v1.PurgedExample{
+			{ID: "exmp_1", TenantID: "default"},
+			{ID: "exmp_2", TenantID: "acme"},
+		}, purged)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - purge examples", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectQuery("DELETE FROM examples WHERE id IN ( SELECT id FROM examples WHERE deleted_at < $1 LIMIT $2 FOR UPDATE SKIP LOCKED ) RETURNING id, tenant_id").WithArgs(sqlmock.AnyArg(), 100).WillReturnError(errors.NewInternalServerError("error"))
+
+		purged, err := sqlxDB.PurgeExamples(context.Background(), time.Now(), 100)
+		assert.Nil(t, purged)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
@@ -0,0 +1,84 @@
+package database_postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
+
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+)
+
+// exportFetchSize is the number of rows read from the export cursor at once,
+// which bounds the memory used by an export.
+const exportFetchSize = 500
+
+// ExportExamples calls fn with every example of the tenant selected by filter,
+// oldest first. Rows are read from a server-side cursor, so that the examples
+// are never all loaded at once. It stops at the first error returned by fn.
+func (d *dbClient) ExportExamples(ctx context.Context, filter entities_example_v1.ExportFilter, fn func(*entities_example_v1.Example) error) error {
+	conditions := []string{"tenant_id = $1", "deleted_at IS NULL"}
+	args := []interface{}{pkg_tenant.FromContext(ctx)}
+
+	if !filter.CreatedAfter.IsZero() {
+		args = append(args, filter.CreatedAfter)
+		conditions = append(conditions, fmt.Sprintf("created_at > $%d", len(args)))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		args = append(args, filter.CreatedBefore)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	// cursors only live within a transaction
+	tx, err := d.reader(ctx).DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.ExportExamples: failed to begin transaction: %v", err.Error())
+		return errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.ExportExamples: failed to begin transaction: %v", err.Error()))
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+		DECLARE examples_export NO SCROLL CURSOR FOR
+			SELECT
+				id,
+				description,
+				created_at,
+				updated_at,
+				deleted_at
+			FROM
+				examples
+			WHERE
+				%s
+			ORDER BY
+				created_at, id
+	`, strings.Join(conditions, " AND ")), args...)
+	if err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.ExportExamples: failed to declare cursor: %v", err.Error())
+		return errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.ExportExamples: failed to declare cursor: %v", err.Error()))
+	}
+
+	for {
+		examples, err := scanExamples(tx.QueryContext(ctx, fmt.Sprintf("FETCH %d FROM examples_export", exportFetchSize)))
+		if err != nil {
+			log.Error().Err(err).
+				Msgf("database.postgres.dbClient.ExportExamples: failed to fetch examples: %v", err.Error())
+			return errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.ExportExamples: failed to fetch examples: %v", err.Error()))
+		}
+
+		for _, example := range examples {
+			if err := fn(example); err != nil {
+				return err
+			}
+		}
+
+		if len(examples) < exportFetchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package database_postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	"github.com/teyz/go-svc-template/pkg/errors"
+)
+
+func Test_ExportExamples(t *testing.T) {
+	t.Run("ok - export examples in pages", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		createdAfter := time.Now().Add(-time.Hour)
+		createdBefore := time.Now()
+
+		full := sqlmock.NewRows([]string{"id", "description", "created_at", "updated_at", "deleted_at"})
+		for i := 0; i < exportFetchSize; i++ {
+			full.AddRow(fmt.Sprintf("exmp_%d", i), "hello world !", createdAfter, createdAfter, nil)
+		}
+		last := sqlmock.NewRows([]string{"id", "description", "created_at", "updated_at", "deleted_at"}).
+			AddRow("exmp_last", "hello world !", createdAfter, createdAfter, nil)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DECLARE examples_export NO SCROLL CURSOR FOR SELECT id, description, created_at, updated_at, deleted_at FROM examples WHERE tenant_id = $1 AND deleted_at IS NULL AND created_at > $2 AND created_at < $3 ORDER BY created_at, id").
+			WithArgs("default", createdAfter, createdBefore).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(fmt.Sprintf("FETCH %d FROM examples_export", exportFetchSize)).WillReturnRows(full)
+		mock.ExpectQuery(fmt.Sprintf("FETCH %d FROM examples_export", exportFetchSize)).WillReturnRows(last)
+		mock.ExpectRollback()
+
+		count := 0
+		err = sqlxDB.ExportExamples(context.Background(), entities_example_v1.ExportFilter{
+			CreatedAfter:  createdAfter,
+			CreatedBefore: createdBefore,
+		}, func(example *entities_example_v1.Example) error {
+			count++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, exportFetchSize+1, count)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - export examples stopped by the callback", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		rows := sqlmock.NewRows([]string{"id", "description", "created_at", "updated_at", "deleted_at"}).
+			AddRow("exmp_1", "hello world !", time.Now(), time.Now(), nil).
+			AddRow("exmp_2", "hello world !", time.Now(), time.Now(), nil)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DECLARE examples_export NO SCROLL CURSOR FOR SELECT id, description, created_at, updated_at, deleted_at FROM examples WHERE tenant_id = $1 AND deleted_at IS NULL ORDER BY created_at, id").
+			WithArgs("default").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(fmt.Sprintf("FETCH %d FROM examples_export", exportFetchSize)).WillReturnRows(rows)
+		mock.ExpectRollback()
+
+		stop := errors.NewInternalServerError("broken pipe")
+
+		count := 0
+		err = sqlxDB.ExportExamples(context.Background(), entities_example_v1.ExportFilter{}, func(example *entities_example_v1.Example) error {
+			count++
+			return stop
+		})
+		assert.Equal(t, stop, err)
+		assert.Equal(t, 1, count)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - export examples", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DECLARE examples_export").WillReturnError(errors.NewInternalServerError("error"))
+		mock.ExpectRollback()
+
+		err = sqlxDB.ExportExamples(context.Background(), entities_example_v1.ExportFilter{}, func(example *entities_example_v1.Example) error {
+			return nil
+		})
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,342 @@
+package database_postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/teyz/go-svc-template/pkg/constants"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
+
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	entities_import_v1 "github.com/teyz/go-svc-template/internal/entities/import/v1"
+)
+
+func scanImport(row scanner, withData bool) (*entities_import_v1.Import, error) {
+	imp := &entities_import_v1.Import{}
+	var importError sql.NullString
+
+	dest := []interface{}{
+		&imp.ID,
+		&imp.TenantID,
+		&imp.Actor,
+		&imp.Format,
+		&imp.Status,
+		&imp.TotalRows,
+		&imp.ProcessedRows,
+		&imp.SucceededRows,
+		&imp.FailedRows,
+		&importError,
+		&imp.StartedAt,
+		&imp.CompletedAt,
+		&imp.CreatedAt,
+		&imp.UpdatedAt,
+	}
+	if withData {
+		dest = append(dest, &imp.Data)
+	}
+
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	imp.Error = importError.String
+
+	return imp, nil
+}
+
+// CreateImport stores a pending import of the tenant.
+func (d *dbClient) CreateImport(ctx context.Context, imp *entities_import_v1.Import) error {
+	now := time.Now()
+
+	imp.ID = constants.GenerateDataPrefixWithULID(constants.Import)
+	imp.TenantID = pkg_tenant.FromContext(ctx)
+	imp.Status = entities_import_v1.StatusPending
+	imp.CreatedAt = now
+	imp.UpdatedAt = now
+
+	_, err := d.writer(ctx).ExecContext(ctx,
+		`INSERT INTO
+			imports (
+				id,
+				tenant_id,
+				actor,
+				format,
+				status,
+				data,
+				total_rows,
+				created_at,
+				updated_at
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`,
+		imp.ID, imp.TenantID, imp.Actor, imp.Format, imp.Status, imp.Data, imp.TotalRows, now, now)
+	if err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.CreateImport: failed to create import: %v", err.Error())
+		return errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.CreateImport: failed to create import: %v", err.Error()))
+	}
+
+	return nil
+}
+
+// GetImportByID returns an import of the tenant, without its data. It reads the
+// primary so that the progress of an import is never behind.
+func (d *dbClient) GetImportByID(ctx context.Context, id string) (*entities_import_v1.Import, error) {
+	imp, err := scanImport(d.writer(ctx).QueryRowContext(ctx, `
+		SELECT
+			id,
+			tenant_id,
+			actor,
+			format,
+			status,
+			total_rows,
+			processed_rows,
+			succeeded_rows,
+			failed_rows,
+			error,
+			started_at,
+			completed_at,
+			created_at,
+			updated_at
+		FROM
+			imports
+		WHERE
+			id = $1 AND tenant_id = $2
+	`, id, pkg_tenant.FromContext(ctx)), false)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Error().Err(err).
+				Str("id", id).
+				Msgf("database.postgres.dbClient.GetImportByID: import with id: %s not found", id)
+			return nil, errors.NewNotFoundError(fmt.Sprintf("database.postgres.dbClient.GetImportByID: import with id: %s not found", id))
+		}
+
+		log.Error().Err(err).
+			Str("id", id).
+			Msgf("database.postgres.dbClient.GetImportByID: failed to get import by id: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.GetImportByID: failed to get import by id: %v", err.Error()))
+	}
+
+	return imp, nil
+}
+
+// FetchImportErrors returns the rejected rows of an import of the tenant, in order.
+func (d *dbClient) FetchImportErrors(ctx context.Context, id string) ([]*entities_import_v1.RowError, error) {
+	rows, err := d.writer(ctx).QueryContext(ctx, `
+		SELECT
+			import_errors.row_number,
+			import_errors.message
+		FROM
+			import_errors
+			JOIN imports ON imports.id = import_errors.import_id
+		WHERE
+			import_errors.import_id = $1 AND imports.tenant_id = $2
+		ORDER BY
+			import_errors.row_number
+	`, id, pkg_tenant.FromContext(ctx))
+	if err != nil {
+		log.Error().Err(err).
+			Str("id", id).
+			Msgf("database.postgres.dbClient.FetchImportErrors: failed to get import errors: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.FetchImportErrors: failed to get import errors: %v", err.Error()))
+	}
+	defer rows.Close()
+
+	rowErrors := make([]*entities_import_v1.RowError, 0)
+
+	for rows.Next() {
+		rowError := &entities_import_v1.RowError{}
+
+		if err := rows.Scan(&rowError.Row, &rowError.Message); err != nil {
+			log.Error().Err(err).
+				Str("id", id).
+				Msgf("database.postgres.dbClient.FetchImportErrors: failed to scan import error: %v", err.Error())
+			return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.FetchImportErrors: failed to scan import error: %v", err.Error()))
+		}
+
+		rowErrors = append(rowErrors, rowError)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error().Err(err).
+			Str("id", id).
+			Msgf("database.postgres.dbClient.FetchImportErrors: failed to read import errors: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.FetchImportErrors: failed to read import errors: %v", err.Error()))
+	}
+
+	return rowErrors, nil
+}
+
+// ClaimImport marks the oldest pending import of any tenant as processing and
+// returns it with its data. Imports left processing since before staleBefore,
+// by an instance that stopped, are claimed again. It returns a NotFoundError
+// when there is no import to process.
+func (d *dbClient) ClaimImport(ctx context.Context, staleBefore time.Time) (*entities_import_v1.Import, error) {
+	imp, err := scanImport(d.writer(ctx).QueryRowContext(ctx, `
+		UPDATE
+			imports
+		SET
+			status = $1,
+			started_at = COALESCE(started_at, NOW())
+		WHERE
+			id = (
+				SELECT
+					id
+				FROM
+					imports
+				WHERE
+					status = $2 OR (status = $1 AND updated_at < $3)
+				ORDER BY
+					created_at
+				LIMIT 1
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING
+			id,
+			tenant_id,
+			actor,
+			format,
+			status,
+			total_rows,
+			processed_rows,
+			succeeded_rows,
+			failed_rows,
+			error,
+			started_at,
+			completed_at,
+			created_at,
+			updated_at,
+			data
+	`, entities_import_v1.StatusProcessing, entities_import_v1.StatusPending, staleBefore), true)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("database.postgres.dbClient.ClaimImport: no import to process")
+		}
+
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.ClaimImport: failed to claim import: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.ClaimImport: failed to claim import: %v", err.Error()))
+	}
+
+	return imp, nil
+}
+
+// ApplyImportChunk creates the examples of the rows start to end of an import,
+// records its rejected rows and moves its progress to end, all in a transaction.
+// The progress only moves when the import is still processing from start, which
+// locks it until the transaction ends, so that a chunk is never applied twice
+// by instances having both claimed the import. It returns an
+// OutdatedResourceError when another instance got ahead. The examples belong to
+// the tenant of the context.
+func (d *dbClient) ApplyImportChunk(ctx context.Context, id string, start int, end int, descriptions []string, language string, rowErrors []*entities_import_v1.RowError) ([]*entities_example_v1.Example, error) {
+	var examples []*entities_example_v1.Example
+
+	err := d.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		examples, err = d.applyImportChunk(ctx, id, start, end, descriptions, language, rowErrors)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return examples, nil
+}
+
+func (d *dbClient) applyImportChunk(ctx context.Context, id string, start int, end int, descriptions []string, language string, rowErrors []*entities_import_v1.RowError) ([]*entities_example_v1.Example, error) {
+	tx := d.writer(ctx)
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE
+			imports
+		SET
+			processed_rows = $3,
+			succeeded_rows = succeeded_rows + $4,
+			failed_rows = failed_rows + $5
+		WHERE
+			id = $1 AND processed_rows = $2 AND status = $6
+	`, id, start, end, len(descriptions), len(rowErrors), entities_import_v1.StatusProcessing)
+	if err != nil {
+		log.Error().Err(err).
+			Str("id", id).
+			Msgf("database.postgres.dbClient.ApplyImportChunk: failed to update import progress: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.ApplyImportChunk: failed to update import progress: %v", err.Error()))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).
+			Str("id", id).
+			Msgf("database.postgres.dbClient.ApplyImportChunk: failed to update import progress: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.ApplyImportChunk: failed to update import progress: %v", err.Error()))
+	}
+	if affected != 1 {
+		log.Warn().
+			Str("id", id).
+			Int("start", start).
+			Msg("database.postgres.dbClient.ApplyImportChunk: import is no longer processing from this chunk")
+		return nil, errors.NewOutdatedResourceError(fmt.Sprintf("database.postgres.dbClient.ApplyImportChunk: import with id: %s is no longer processing from row %d", id, start))
+	}
+
+	examples := make([]*entities_example_v1.Example, 0)
+	if len(descriptions) > 0 {
+		examples, err = insertExamples(ctx, tx, descriptions, language)
+		if err != nil {
+			log.Error().Err(err).
+				Str("id", id).
+				Msgf("database.postgres.dbClient.ApplyImportChunk: failed to create examples: %v", err.Error())
+			return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.ApplyImportChunk: failed to create examples: %v", err.Error()))
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		args := make([]interface{}, 0, len(rowErrors)*3)
+		for _, rowError := range rowErrors {
+			args = append(args, id, rowError.Row, rowError.Message)
+		}
+
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+			INSERT INTO
+				import_errors (
+					import_id,
+					row_number,
+					message
+				)
+				VALUES %s
+		`, valuesPlaceholders(len(rowErrors), 3)), args...)
+		if err != nil {
+			log.Error().Err(err).
+				Str("id", id).
+				Msgf("database.postgres.dbClient.ApplyImportChunk: failed to create import errors: %v", err.Error())
+			return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.ApplyImportChunk: failed to create import errors: %v", err.Error()))
+		}
+	}
+
+	return examples, nil
+}
+
+// CompleteImport ends an import with status, along with the error that made it
+// fail, if any. The uploaded file is dropped as it is no longer needed.
+func (d *dbClient) CompleteImport(ctx context.Context, id string, status string, importError string) error {
+	_, err := d.writer(ctx).ExecContext(ctx, `
+		UPDATE
+			imports
+		SET
+			status = $2,
+			error = NULLIF($3, ''),
+			data = NULL,
+			completed_at = NOW()
+		WHERE
+			id = $1
+	`, id, status, importError)
+	if err != nil {
+		log.Error().Err(err).
+			Str("id", id).
+			Msgf("database.postgres.dbClient.CompleteImport: failed to complete import: %v", err.Error())
+		return errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.CompleteImport: failed to complete import: %v", err.Error()))
+	}
+
+	return nil
+}
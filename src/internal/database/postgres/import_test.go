@@ -0,0 +1,211 @@
+package database_postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	entities_import_v1 "github.com/teyz/go-svc-template/internal/entities/import/v1"
+	"github.com/teyz/go-svc-template/pkg/constants"
+	"github.com/teyz/go-svc-template/pkg/errors"
+)
+
+func Test_CreateImport(t *testing.T) {
+	t.Run("ok - create import", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectExec("INSERT INTO imports ( id, tenant_id, actor, format, status, data, total_rows, created_at, updated_at ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)").
+			WithArgs(sqlmock.AnyArg(), "default", "user", "csv", "pending", []byte("description\nfirst\n"), 1, AnyTime{}, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		imp := &entities_import_v1.Import{
+			Format:    entities_import_v1.FormatCSV,
+			TotalRows: 1,
+			Actor:     "user",
+			Data:      []byte("description\nfirst\n"),
+		}
+
+		err = sqlxDB.CreateImport(context.Background(), imp)
+		assert.NoError(t, err)
+		assert.True(t, constants.Import.IsValid(imp.ID))
+		assert.Equal(t, entities_import_v1.StatusPending, imp.Status)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_GetImportByID(t *testing.T) {
+	t.Run("nok - import not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectQuery("SELECT").WithArgs("impt_1", "default").WillReturnError(sql.ErrNoRows)
+
+		imp, err := sqlxDB.GetImportByID(context.Background(), "impt_1")
+		assert.Nil(t, imp)
+		assert.True(t, errors.IsNotFoundError(err))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_ClaimImport(t *testing.T) {
+	columns := []string{"id", "tenant_id", "actor", "format", "status", "total_rows", "processed_rows", "succeeded_rows", "failed_rows", "error", "started_at", "completed_at", "created_at", "updated_at", "data"}
+
+	t.Run("ok - claim import", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		now := time.Now()
+		staleBefore := now.Add(-time.Minute)
+
+		mock.ExpectQuery("UPDATE imports").
+			WithArgs("processing", "pending", staleBefore).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("impt_1", "acme", "user", "ndjson", "processing", 2, 1, 1, 0, nil, now, nil, now, now, []byte("{}\n{}\n")))
+
+		imp, err := sqlxDB.ClaimImport(context.Background(), staleBefore)
+		assert.NoError(t, err)
+		assert.Equal(t, "acme", imp.TenantID)
+		assert.Equal(t, 1, imp.ProcessedRows)
+		assert.Equal(t, "", imp.Error)
+		assert.Nil(t, imp.CompletedAt)
+		assert.Equal(t, []byte("{}\n{}\n"), imp.Data)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - no import to claim", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectQuery("UPDATE imports").WillReturnRows(sqlmock.NewRows(columns))
+
+		imp, err := sqlxDB.ClaimImport(context.Background(), time.Now())
+		assert.Nil(t, imp)
+		assert.True(t, errors.IsNotFoundError(err))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_ApplyImportChunk(t *testing.T) {
+	progress := "UPDATE imports SET processed_rows = $3, succeeded_rows = succeeded_rows + $4, failed_rows = failed_rows + $5 WHERE id = $1 AND processed_rows = $2 AND status = $6"
+
+	t.Run("ok - apply import chunk", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(progress).
+			WithArgs("impt_1", 0, 2, 1, 1, "processing").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO examples ( id, tenant_id, description, search_language, created_at, updated_at ) VALUES ($1, $2, $3, $4, $5, $6)").
+			WithArgs(sqlmock.AnyArg(), "default", "first", "english", AnyTime{}, AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO import_errors ( import_id, row_number, message ) VALUES ($1, $2, $3)").
+			WithArgs("impt_1", 2, "description is required").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		examples, err := sqlxDB.ApplyImportChunk(context.Background(), "impt_1", 0, 2, []string{"first"}, "english", []*entities_import_v1.RowError{
+			{Row: 2, Message: "description is required"},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, examples, 1)
+		assert.Equal(t, "first", examples[0].Description)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - apply import chunk - taken over by another instance", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(progress).
+			WithArgs("impt_1", 0, 1, 1, 0, "processing").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		examples, err := sqlxDB.ApplyImportChunk(context.Background(), "impt_1", 0, 1, []string{"first"}, "english", nil)
+		assert.Nil(t, examples)
+		assert.True(t, errors.IsOutdatedResourceError(err))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - apply import chunk", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE imports").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO examples").WillReturnError(errors.NewInternalServerError("error"))
+		mock.ExpectRollback()
+
+		examples, err := sqlxDB.ApplyImportChunk(context.Background(), "impt_1", 0, 1, []string{"first"}, "english", nil)
+		assert.Nil(t, examples)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func Test_CompleteImport(t *testing.T) {
+	t.Run("ok - complete import", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectExec("UPDATE imports SET status = $2, error = NULLIF($3, ''), data = NULL, completed_at = NOW() WHERE id = $1").
+			WithArgs("impt_1", "completed", "").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = sqlxDB.CompleteImport(context.Background(), "impt_1", entities_import_v1.StatusCompleted, "")
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -2,19 +2,107 @@ package database_postgres
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/rs/zerolog/log"
 
 	"github.com/teyz/go-svc-template/internal/database"
+	pkg_postgres "github.com/teyz/go-svc-template/pkg/database/postgres"
+	"github.com/teyz/go-svc-template/pkg/errors"
 )
 
 type dbClient struct {
 	connection *sqlx.DB
+	replicas   *pkg_postgres.ReplicaSet
 }
 
-func NewClient(ctx context.Context, db *sqlx.DB) database.Database {
+func NewClient(ctx context.Context, db *sqlx.DB, replicas *pkg_postgres.ReplicaSet) database.Database {
 	return &dbClient{
 		connection: db,
+		replicas:   replicas,
 	}
 }
+
+// reader returns the connection to use for reads: a healthy replica when there
+// is one, the primary otherwise.
+func (d *dbClient) reader(ctx context.Context) *sqlx.DB {
+	if replica := d.replicas.Reader(ctx); replica != nil {
+		return replica
+	}
+
+	return d.connection
+}
+
+// querier runs statements on the primary or within a transaction.
+type querier interface {
+	execer
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+// writer returns the transaction of ctx, or the primary outside of one.
+func (d *dbClient) writer(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+
+	return d.connection.DB
+}
+
+// WithTx calls fn with a context whose writes all run in a transaction,
+// committed when fn succeeds and rolled back otherwise. Within a transaction,
+// fn runs in a savepoint so that only its own writes are rolled back.
+func (d *dbClient) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return withSavepoint(ctx, tx, fn)
+	}
+
+	tx, err := d.connection.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.WithTx: failed to begin transaction: %v", err.Error())
+		return errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.WithTx: failed to begin transaction: %v", err.Error()))
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.WithTx: failed to commit transaction: %v", err.Error())
+		return errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.WithTx: failed to commit transaction: %v", err.Error()))
+	}
+
+	return nil
+}
+
+func withSavepoint(ctx context.Context, tx *sql.Tx, fn func(ctx context.Context) error) error {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT nested"); err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.WithTx: failed to create savepoint: %v", err.Error())
+		return errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.WithTx: failed to create savepoint: %v", err.Error()))
+	}
+
+	if err := fn(ctx); err != nil {
+		if _, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT nested"); rollbackErr != nil {
+			log.Error().Err(rollbackErr).
+				Msg("database.postgres.dbClient.WithTx: failed to roll back to savepoint")
+		}
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT nested"); err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.WithTx: failed to release savepoint: %v", err.Error())
+		return errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.WithTx: failed to release savepoint: %v", err.Error()))
+	}
+
+	return nil
+}
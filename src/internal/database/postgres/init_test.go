@@ -0,0 +1,86 @@
+package database_postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/teyz/go-svc-template/pkg/errors"
+
+	entities_audit_v1 "github.com/teyz/go-svc-template/internal/entities/audit/v1"
+)
+
+func Test_WithTx(t *testing.T) {
+	t.Run("ok - commit the writes of fn", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO audit_log").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = sqlxDB.WithTx(context.Background(), func(ctx context.Context) error {
+			return sqlxDB.CreateAuditEntry(ctx, &entities_audit_v1.Entry{})
+		})
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - roll back when fn fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO audit_log").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectRollback()
+
+		err = sqlxDB.WithTx(context.Background(), func(ctx context.Context) error {
+			if err := sqlxDB.CreateAuditEntry(ctx, &entities_audit_v1.Entry{}); err != nil {
+				return err
+			}
+			return errors.NewForbiddenError("error")
+		})
+		assert.True(t, errors.IsForbiddenError(err))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - roll back nested calls to their savepoint", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT nested").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT nested").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO audit_log").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = sqlxDB.WithTx(context.Background(), func(ctx context.Context) error {
+			err := sqlxDB.WithTx(ctx, func(ctx context.Context) error {
+				return errors.NewNotFoundError("error")
+			})
+			assert.True(t, errors.IsNotFoundError(err))
+
+			return sqlxDB.CreateAuditEntry(ctx, &entities_audit_v1.Entry{})
+		})
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,126 @@
+package database_postgres
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
+
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+)
+
+// searchStartSel and searchStopSel delimit the matches in headlines. They are
+// private use characters rather than <mark> tags, so that headlines can be HTML
+// escaped before the matches are highlighted.
+const (
+	searchStartSel = "\uE000"
+	searchStopSel  = "\uE001"
+)
+
+const searchHeadlineOptions = "StartSel=" + searchStartSel + ", StopSel=" + searchStopSel + ", MaxFragments=2, MaxWords=20, MinWords=5"
+
+var searchHighlighter = strings.NewReplacer(searchStartSel, "<mark>", searchStopSel, "</mark>")
+
+// highlightSnippet HTML escapes a headline and wraps its matches in <mark> tags.
+func highlightSnippet(headline string) string {
+	return searchHighlighter.Replace(html.EscapeString(headline))
+}
+
+var searchWordRegexp = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// prefixTSQuery turns text into a tsquery matching every word of text as a
+// prefix. Only letters and digits are kept, so that text can not inject
+// tsquery operators.
+func prefixTSQuery(text string) string {
+	words := searchWordRegexp.FindAllString(text, -1)
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+
+	return strings.Join(words, " & ")
+}
+
+// SearchExamples returns the examples of the tenant matching the query, most
+// relevant first. Soft-deleted examples are never returned.
+func (d *dbClient) SearchExamples(ctx context.Context, query entities_example_v1.SearchQuery) ([]*entities_example_v1.SearchResult, error) {
+	conditions := []string{"tenant_id = $3", "deleted_at IS NULL", "search_vector @@ query"}
+	args := []interface{}{query.Language, prefixTSQuery(query.Text), pkg_tenant.FromContext(ctx)}
+
+	if query.After != nil {
+		args = append(args, query.After.Rank, query.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(ts_rank_cd(search_vector, query) < $%d::real OR (ts_rank_cd(search_vector, query) = $%d::real AND id > $%d))", len(args)-1, len(args)-1, len(args)))
+	}
+	args = append(args, searchHeadlineOptions, query.Limit)
+
+	// snippets are only computed for the page of results
+	rows, err := d.reader(ctx).DB.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			id,
+			description,
+			created_at,
+			updated_at,
+			rank,
+			ts_headline(search_language, description, query, $%d)
+		FROM (
+			SELECT
+				id,
+				description,
+				search_language,
+				created_at,
+				updated_at,
+				query,
+				ts_rank_cd(search_vector, query) AS rank
+			FROM
+				examples,
+				to_tsquery($1::regconfig, $2) query
+			WHERE
+				%s
+			ORDER BY
+				rank DESC, id
+			LIMIT $%d
+		) results
+		ORDER BY
+			rank DESC, id
+	`, len(args)-1, strings.Join(conditions, " AND "), len(args)), args...)
+	if err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.SearchExamples: failed to search examples: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.SearchExamples: failed to search examples: %v", err.Error()))
+	}
+	defer rows.Close()
+
+	results := make([]*entities_example_v1.SearchResult, 0)
+
+	for rows.Next() {
+		result := &entities_example_v1.SearchResult{}
+
+		err := rows.Scan(
+			&result.ID,
+			&result.Description,
+			&result.CreatedAt,
+			&result.UpdatedAt,
+			&result.Rank,
+			&result.Snippet,
+		)
+		if err != nil {
+			log.Error().Err(err).
+				Msgf("database.postgres.dbClient.SearchExamples: failed to scan search result: %v", err.Error())
+			return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.SearchExamples: failed to scan search result: %v", err.Error()))
+		}
+
+		result.Snippet = highlightSnippet(result.Snippet)
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error().Err(err).
+			Msgf("database.postgres.dbClient.SearchExamples: failed to read search results: %v", err.Error())
+		return nil, errors.NewInternalServerError(fmt.Sprintf("database.postgres.dbClient.SearchExamples: failed to read search results: %v", err.Error()))
+	}
+
+	return results, nil
+}
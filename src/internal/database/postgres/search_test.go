@@ -0,0 +1,110 @@
+package database_postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
+)
+
+func Test_PrefixTSQuery(t *testing.T) {
+	assert.Equal(t, "hello:* & wor:*", prefixTSQuery("hello wor"))
+	assert.Equal(t, "café:* & 42:*", prefixTSQuery("  café & 42 | !"))
+	assert.Equal(t, "drop:* & table:*", prefixTSQuery("'drop':* <-> table"))
+	assert.Equal(t, "", prefixTSQuery("&|!"))
+}
+
+func Test_HighlightSnippet(t *testing.T) {
+	assert.Equal(t, "<mark>hello</mark> world !", highlightSnippet(searchStartSel+"hello"+searchStopSel+" world !"))
+	assert.Equal(t, "&lt;script&gt;<mark>alert</mark>(&#39;x&#39;)&lt;/script&gt;", highlightSnippet("<script>"+searchStartSel+"alert"+searchStopSel+"('x')</script>"))
+	assert.Equal(t, "a &lt;mark&gt; b", highlightSnippet("a <mark> b"))
+}
+
+func Test_SearchExamples(t *testing.T) {
+	searchQuery := func(cursor string, headline int, limit int) string {
+		return fmt.Sprintf("SELECT id, description, created_at, updated_at, rank, ts_headline(search_language, description, query, $%d) "+
+			"FROM ( SELECT id, description, search_language, created_at, updated_at, query, ts_rank_cd(search_vector, query) AS rank "+
+			"FROM examples, to_tsquery($1::regconfig, $2) query "+
+			"WHERE tenant_id = $3 AND deleted_at IS NULL AND search_vector @@ query%s "+
+			"ORDER BY rank DESC, id LIMIT $%d ) results ORDER BY rank DESC, id", headline, cursor, limit)
+	}
+
+	t.Run("ok - search examples", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		rows := sqlmock.NewRows([]string{"id", "description", "created_at", "updated_at", "rank", "ts_headline"}).
+			AddRow("exmp_1", "hello world !", time.Now(), time.Now(), 0.1, searchStartSel+"hello"+searchStopSel+" world !")
+
+		mock.ExpectQuery(searchQuery("", 4, 5)).
+			WithArgs("english", "hel:*", "acme", searchHeadlineOptions, 10).WillReturnRows(rows)
+
+		results, err := sqlxDB.SearchExamples(pkg_tenant.WithTenant(context.Background(), "acme"), entities_example_v1.SearchQuery{
+			Text:     "hel",
+			Language: "english",
+			Limit:    10,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+
+		assert.Equal(t, "exmp_1", results[0].ID)
+		assert.Equal(t, float32(0.1), results[0].Rank)
+		assert.Equal(t, "<mark>hello</mark> world !", results[0].Snippet)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("ok - search examples after cursor", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		rows := sqlmock.NewRows([]string{"id", "description", "created_at", "updated_at", "rank", "ts_headline"})
+
+		mock.ExpectQuery(searchQuery(" AND (ts_rank_cd(search_vector, query) < $4::real OR (ts_rank_cd(search_vector, query) = $4::real AND id > $5))", 6, 7)).
+			WithArgs("french", "bonjour:*", "default", float32(0.5), "exmp_1", searchHeadlineOptions, 10).WillReturnRows(rows)
+
+		results, err := sqlxDB.SearchExamples(context.Background(), entities_example_v1.SearchQuery{
+			Text:     "bonjour",
+			Language: "french",
+			After:    &entities_example_v1.SearchCursor{Rank: 0.5, ID: "exmp_1"},
+			Limit:    10,
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, results)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - search examples", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		sqlxDB := &dbClient{
+			connection: sqlx.NewDb(db, "sqlmock"),
+		}
+
+		mock.ExpectQuery(searchQuery("", 4, 5)).WillReturnError(errors.NewInternalServerError("error"))
+
+		results, err := sqlxDB.SearchExamples(context.Background(), entities_example_v1.SearchQuery{Text: "hello", Language: "english", Limit: 10})
+		assert.Nil(t, results)
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,34 @@
+package dto_example_v1
+
+import "time"
+
+// Example is an example as returned by the v1 routes.
+type Example struct {
+	ID          string     `json:"id"`
+	Description string     `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// SearchResult is an example matching a search, along with its relevance and
+// its description with the matching words highlighted.
+type SearchResult struct {
+	Example
+	Rank    float32 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// BatchItemResult is the outcome of an item of a batch, identified by its
+// position in the batch. Error is empty when the item was applied.
+type BatchItemResult struct {
+	Index int    `json:"index"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type BatchResult struct {
+	Results   []*BatchItemResult `json:"results"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+}
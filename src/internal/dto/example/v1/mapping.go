@@ -0,0 +1,76 @@
+package dto_example_v1
+
+import (
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+)
+
+func FromEntity(example *entities_example_v1.Example) *Example {
+	return &Example{
+		ID:          example.ID,
+		Description: example.Description,
+		CreatedAt:   example.CreatedAt,
+		UpdatedAt:   example.UpdatedAt,
+		DeletedAt:   example.DeletedAt,
+	}
+}
+
+func FromEntities(examples []*entities_example_v1.Example) []*Example {
+	dtos := make([]*Example, 0, len(examples))
+	for _, example := range examples {
+		dtos = append(dtos, FromEntity(example))
+	}
+
+	return dtos
+}
+
+func SearchResultsFromEntities(results []*entities_example_v1.SearchResult) []*SearchResult {
+	dtos := make([]*SearchResult, 0, len(results))
+	for _, result := range results {
+		dtos = append(dtos, &SearchResult{
+			Example: *FromEntity(&result.Example),
+			Rank:    result.Rank,
+			Snippet: result.Snippet,
+		})
+	}
+
+	return dtos
+}
+
+func BatchResultFromEntity(result *entities_example_v1.BatchResult) *BatchResult {
+	items := make([]*BatchItemResult, 0, len(result.Results))
+	for _, item := range result.Results {
+		items = append(items, &BatchItemResult{
+			Index: item.Index,
+			ID:    item.ID,
+			Error: item.Error,
+		})
+	}
+
+	return &BatchResult{
+		Results:   items,
+		Succeeded: result.Succeeded,
+		Failed:    result.Failed,
+	}
+}
+
+// Sparse returns the given fields of an example, keyed by their v1 JSON name.
+func Sparse(example *entities_example_v1.Example, fields []string) map[string]interface{} {
+	sparse := make(map[string]interface{}, len(fields))
+
+	for _, field := range fields {
+		switch field {
+		case entities_example_v1.FieldID:
+			sparse["id"] = example.ID
+		case entities_example_v1.FieldDescription:
+			sparse["description"] = example.Description
+		case entities_example_v1.FieldCreatedAt:
+			sparse["created_at"] = example.CreatedAt
+		case entities_example_v1.FieldUpdatedAt:
+			sparse["updated_at"] = example.UpdatedAt
+		case entities_example_v1.FieldDeletedAt:
+			sparse["deleted_at"] = example.DeletedAt
+		}
+	}
+
+	return sparse
+}
@@ -0,0 +1,30 @@
+package dto_example_v2
+
+import "time"
+
+// statuses of an example
+const (
+	StatusActive  = "active"
+	StatusDeleted = "deleted"
+)
+
+// Example is an example as returned by the v2 routes, its lifecycle being
+// grouped apart from its content.
+type Example struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	Lifecycle   Lifecycle `json:"lifecycle"`
+}
+
+type Lifecycle struct {
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// List is a list of examples along with its size.
+type List struct {
+	Items []*Example `json:"items"`
+	Count int        `json:"count"`
+}
@@ -0,0 +1,35 @@
+package dto_example_v2
+
+import (
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+)
+
+func FromEntity(example *entities_example_v1.Example) *Example {
+	status := StatusActive
+	if example.DeletedAt != nil {
+		status = StatusDeleted
+	}
+
+	return &Example{
+		ID:          example.ID,
+		Description: example.Description,
+		Status:      status,
+		Lifecycle: Lifecycle{
+			CreatedAt: example.CreatedAt,
+			UpdatedAt: example.UpdatedAt,
+			DeletedAt: example.DeletedAt,
+		},
+	}
+}
+
+func ListFromEntities(examples []*entities_example_v1.Example) *List {
+	items := make([]*Example, 0, len(examples))
+	for _, example := range examples {
+		items = append(items, FromEntity(example))
+	}
+
+	return &List{
+		Items: items,
+		Count: len(items),
+	}
+}
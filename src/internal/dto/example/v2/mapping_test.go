@@ -0,0 +1,37 @@
+package dto_example_v2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+)
+
+func Test_FromEntity(t *testing.T) {
+	now := time.Now()
+
+	t.Run("ok - map active example", func(t *testing.T) {
+		example := FromEntity(&entities_example_v1.Example{ID: "exmp_1", Description: "hello world !", CreatedAt: now, UpdatedAt: now})
+
+		assert.Equal(t, &Example{
+			ID:          "exmp_1",
+			Description: "hello world !",
+			Status:      StatusActive,
+			Lifecycle:   Lifecycle{CreatedAt: now, UpdatedAt: now},
+		}, example)
+	})
+	t.Run("ok - map deleted example", func(t *testing.T) {
+		example := FromEntity(&entities_example_v1.Example{ID: "exmp_1", DeletedAt: &now})
+
+		assert.Equal(t, StatusDeleted, example.Status)
+		assert.Equal(t, &now, example.Lifecycle.DeletedAt)
+	})
+}
+
+func Test_ListFromEntities(t *testing.T) {
+	list := ListFromEntities([]*entities_example_v1.Example{{ID: "exmp_1"}, {ID: "exmp_2"}})
+
+	assert.Equal(t, 2, list.Count)
+	assert.Equal(t, "exmp_2", list.Items[1].ID)
+}
@@ -0,0 +1,25 @@
+package entities_apikey_v1
+
+import "time"
+
+type APIKey struct {
+	ID string `json:"id"`
+	// TenantID is the tenant the key is bound to
+	TenantID string `json:"tenant_id"`
+	// Name describes the caller owning the key
+	Name string `json:"name"`
+	// Prefix is the start of the key, to recognize it without storing it
+	Prefix     string     `json:"prefix"`
+	Hash       string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// IsExpired reports whether the key has expired at now.
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
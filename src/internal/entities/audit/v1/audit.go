@@ -0,0 +1,42 @@
+package entities_audit_v1
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+
+	ResourceTypeExample = "example"
+	ResourceTypeAPIKey  = "api_key"
+)
+
+type Entry struct {
+	ID           string `json:"id"`
+	Actor        string `json:"actor"`
+	Action       string `json:"action"`
+	ResourceType string `json:"resource_type"`
+	ResourceID   string `json:"resource_id"`
+	// Before and After are the resource before and after the mutation, when known
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	RequestID string          `json:"request_id"`
+	IP        string          `json:"ip"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Filter selects audit entries, zero fields match every entry.
+type Filter struct {
+	Actor        string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Since        time.Time
+	Until        time.Time
+	Limit        int
+}
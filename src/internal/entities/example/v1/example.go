@@ -3,8 +3,85 @@ package entities_example_v1
 import "time"
 
 type Example struct {
-	ID          string    `json:"id"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string     `json:"id"`
+	Description string     `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// fields of an example, named after their JSON key
+const (
+	FieldID          = "id"
+	FieldDescription = "description"
+	FieldCreatedAt   = "created_at"
+	FieldUpdatedAt   = "updated_at"
+	FieldDeletedAt   = "deleted_at"
+)
+
+// Fields lists the fields of an example in the order they are returned.
+var Fields = []string{FieldID, FieldDescription, FieldCreatedAt, FieldUpdatedAt, FieldDeletedAt}
+
+// PurgedExample identifies an example removed for good.
+type PurgedExample struct {
+	ID       string
+	TenantID string
+}
+
+// Change is an example before and after a mutation.
+type Change struct {
+	Before *Example
+	After  *Example
+}
+
+// SearchResult is an example matching a search, along with its relevance and
+// its description with the matching words highlighted.
+type SearchResult struct {
+	Example
+	Rank    float32 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// SearchQuery selects the examples whose description matches every word of
+// Text, including words it is a prefix of. Results come after the After cursor
+// when it is set.
+type SearchQuery struct {
+	Text     string
+	Language string
+	After    *SearchCursor
+	Limit    int
+}
+
+// SearchCursor is the position of a result in the ordering of a search.
+type SearchCursor struct {
+	Rank float32 `json:"rank"`
+	ID   string  `json:"id"`
+}
+
+// modes of a batch
+const (
+	// BatchModeAtomic applies every item of a batch or none of them
+	BatchModeAtomic = "atomic"
+	// BatchModeBestEffort applies the valid items of a batch and reports the others
+	BatchModeBestEffort = "best_effort"
+)
+
+// BatchItemResult is the outcome of an item of a batch, identified by its
+// position in the batch. Error is empty when the item was applied.
+type BatchItemResult struct {
+	Index int    `json:"index"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type BatchResult struct {
+	Results   []*BatchItemResult `json:"results"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+}
+
+// ExportFilter selects the examples to export, the zero value selecting all of them.
+type ExportFilter struct {
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
 }
@@ -0,0 +1,44 @@
+package entities_import_v1
+
+import "time"
+
+// formats of an import
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// statuses of an import
+const (
+	StatusPending    = "pending"
+	StatusProcessing = "processing"
+	StatusCompleted  = "completed"
+	StatusFailed     = "failed"
+)
+
+// Import is an upload of examples processed in the background. Rows are
+// numbered from 1, the header of a CSV upload excluded.
+type Import struct {
+	ID            string     `json:"id"`
+	Format        string     `json:"format"`
+	Status        string     `json:"status"`
+	TotalRows     int        `json:"total_rows"`
+	ProcessedRows int        `json:"processed_rows"`
+	SucceededRows int        `json:"succeeded_rows"`
+	FailedRows    int        `json:"failed_rows"`
+	Error         string     `json:"error,omitempty"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	TenantID string `json:"-"`
+	Actor    string `json:"-"`
+	Data     []byte `json:"-"`
+}
+
+// RowError is the reason a row of an import was rejected.
+type RowError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,47 @@
+package handlers_http_private_apikey_v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	entities_apikey_v1 "github.com/teyz/go-svc-template/internal/entities/apikey/v1"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type CreateAPIKeyResponse struct {
+	APIKey *entities_apikey_v1.APIKey `json:"api_key"`
+	// Key is only returned once, at creation
+	Key string `json:"key"`
+}
+
+func (h *Handler) CreateAPIKey(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req CreateAPIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("handlers.http.private.apikey.v1.create_api_key.CreateAPIKey: can not bind request")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	if req.Name == "" {
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	apiKey, key, err := h.service.CreateAPIKey(ctx, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	return c.JSON(http.StatusCreated, pkg_http.NewHTTPResponse(http.StatusCreated, pkg_http.MessageSuccess, CreateAPIKeyResponse{
+		APIKey: apiKey,
+		Key:    key,
+	}))
+}
@@ -0,0 +1,26 @@
+package handlers_http_private_apikey_v1
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	entities_apikey_v1 "github.com/teyz/go-svc-template/internal/entities/apikey/v1"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+type FetchAPIKeysResponse struct {
+	APIKeys []*entities_apikey_v1.APIKey `json:"api_keys"`
+}
+
+func (h *Handler) FetchAPIKeys(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	apiKeys, err := h.service.FetchAPIKeys(ctx)
+	if err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	return c.JSON(http.StatusOK, pkg_http.NewHTTPResponse(http.StatusOK, pkg_http.MessageSuccess, FetchAPIKeysResponse{
+		APIKeys: apiKeys,
+	}))
+}
@@ -0,0 +1,17 @@
+package handlers_http_private_apikey_v1
+
+import (
+	"context"
+
+	service_v1 "github.com/teyz/go-svc-template/internal/service/v1"
+)
+
+type Handler struct {
+	service service_v1.APIKeyService
+}
+
+func NewHandler(_ context.Context, service service_v1.APIKeyService) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
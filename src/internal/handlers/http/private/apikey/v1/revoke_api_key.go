@@ -0,0 +1,25 @@
+package handlers_http_private_apikey_v1
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+func (h *Handler) RevokeAPIKey(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id := c.Param("id")
+	if id == "" {
+		log.Error().Msg("handlers.http.private.apikey.v1.revoke_api_key.Handler.RevokeAPIKey: can not get id from context")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	if err := h.service.RevokeAPIKey(ctx, id); err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	return c.JSON(http.StatusOK, pkg_http.NewHTTPResponse(http.StatusOK, pkg_http.MessageSuccess, nil))
+}
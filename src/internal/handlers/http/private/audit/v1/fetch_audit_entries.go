@@ -0,0 +1,62 @@
+package handlers_http_private_audit_v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	entities_audit_v1 "github.com/teyz/go-svc-template/internal/entities/audit/v1"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+type FetchAuditEntriesRequest struct {
+	Actor        string     `query:"actor"`
+	Action       string     `query:"action"`
+	ResourceType string     `query:"resource_type"`
+	ResourceID   string     `query:"resource_id"`
+	Since        *time.Time `query:"since"`
+	Until        *time.Time `query:"until"`
+	Limit        int        `query:"limit"`
+}
+
+type FetchAuditEntriesResponse struct {
+	Entries []*entities_audit_v1.Entry `json:"entries"`
+}
+
+func (h *Handler) FetchAuditEntries(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req FetchAuditEntriesRequest
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("handlers.http.private.audit.v1.fetch_audit_entries.FetchAuditEntries: can not bind request")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	if req.Limit < 0 {
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	filter := entities_audit_v1.Filter{
+		Actor:        req.Actor,
+		Action:       req.Action,
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceID,
+		Limit:        req.Limit,
+	}
+	if req.Since != nil {
+		filter.Since = *req.Since
+	}
+	if req.Until != nil {
+		filter.Until = *req.Until
+	}
+
+	entries, err := h.service.FetchAuditEntries(ctx, filter)
+	if err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	return c.JSON(http.StatusOK, pkg_http.NewHTTPResponse(http.StatusOK, pkg_http.MessageSuccess, FetchAuditEntriesResponse{
+		Entries: entries,
+	}))
+}
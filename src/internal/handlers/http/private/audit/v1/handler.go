@@ -0,0 +1,17 @@
+package handlers_http_private_audit_v1
+
+import (
+	"context"
+
+	service_v1 "github.com/teyz/go-svc-template/internal/service/v1"
+)
+
+type Handler struct {
+	service service_v1.AuditService
+}
+
+func NewHandler(_ context.Context, service service_v1.AuditService) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
@@ -0,0 +1,114 @@
+package handlers_http_private_example_v1
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	dto_example_v1 "github.com/teyz/go-svc-template/internal/dto/example/v1"
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+type CreateExamplesRequest struct {
+	Mode  string                 `json:"mode"`
+	Items []CreateExampleRequest `json:"items"`
+}
+
+type UpdateExampleItem struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+}
+
+type UpdateExamplesRequest struct {
+	Mode  string              `json:"mode"`
+	Items []UpdateExampleItem `json:"items"`
+}
+
+type DeleteExamplesRequest struct {
+	Mode string   `json:"mode"`
+	IDs  []string `json:"ids"`
+}
+
+func (h *Handler) CreateExamples(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req CreateExamplesRequest
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("handlers.http.private.example.v1.batch_examples.Handler.CreateExamples: can not bind request")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	descriptions := make([]string, 0, len(req.Items))
+	for _, item := range req.Items {
+		descriptions = append(descriptions, item.Description)
+	}
+
+	result, err := h.service.CreateExamples(ctx, descriptions, batchMode(req.Mode))
+	if err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	return batchResponse(c, result)
+}
+
+func (h *Handler) UpdateExamples(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req UpdateExamplesRequest
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("handlers.http.private.example.v1.batch_examples.Handler.UpdateExamples: can not bind request")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	examples := make([]*entities_example_v1.Example, 0, len(req.Items))
+	for _, item := range req.Items {
+		examples = append(examples, &entities_example_v1.Example{
+			ID:          item.ID,
+			Description: item.Description,
+		})
+	}
+
+	result, err := h.service.UpdateExamples(ctx, examples, batchMode(req.Mode))
+	if err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	return batchResponse(c, result)
+}
+
+func (h *Handler) DeleteExamples(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req DeleteExamplesRequest
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("handlers.http.private.example.v1.batch_examples.Handler.DeleteExamples: can not bind request")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	result, err := h.service.DeleteExamples(ctx, req.IDs, batchMode(req.Mode))
+	if err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	return batchResponse(c, result)
+}
+
+// batchMode defaults to all-or-nothing.
+func batchMode(mode string) string {
+	if mode == "" {
+		return entities_example_v1.BatchModeAtomic
+	}
+
+	return mode
+}
+
+// batchResponse answers with the per-item results, a batch with failed items
+// only succeeds in best-effort mode.
+func batchResponse(c echo.Context, result *entities_example_v1.BatchResult) error {
+	if result.Succeeded == 0 && result.Failed > 0 {
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, dto_example_v1.BatchResultFromEntity(result)))
+	}
+
+	return c.JSON(http.StatusOK, pkg_http.NewHTTPResponse(http.StatusOK, pkg_http.MessageSuccess, dto_example_v1.BatchResultFromEntity(result)))
+}
@@ -5,7 +5,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
-	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	dto_example_v1 "github.com/teyz/go-svc-template/internal/dto/example/v1"
 	pkg_http "github.com/teyz/go-svc-template/pkg/http"
 )
 
@@ -14,7 +14,7 @@ type CreateExampleRequest struct {
 }
 
 type CreateExampleResponse struct {
-	Example *entities_example_v1.Example `json:"example"`
+	Example *dto_example_v1.Example `json:"example"`
 }
 
 func (h *Handler) CreateExample(c echo.Context) error {
@@ -36,11 +36,6 @@ func (h *Handler) CreateExample(c echo.Context) error {
 	}
 
 	return c.JSON(http.StatusCreated, pkg_http.NewHTTPResponse(http.StatusCreated, pkg_http.MessageSuccess, CreateExampleResponse{
-		Example: &entities_example_v1.Example{
-			ID:          example.ID,
-			Description: example.Description,
-			CreatedAt:   example.CreatedAt,
-			UpdatedAt:   example.UpdatedAt,
-		},
+		Example: dto_example_v1.FromEntity(example),
 	}))
 }
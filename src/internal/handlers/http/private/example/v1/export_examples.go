@@ -0,0 +1,179 @@
+package handlers_http_private_example_v1
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	dto_example_v1 "github.com/teyz/go-svc-template/internal/dto/example/v1"
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+// export formats
+const (
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatCSV    = "csv"
+)
+
+// exportFlushInterval is the number of examples written between two flushes of the response.
+const exportFlushInterval = 100
+
+type ExportExamplesRequest struct {
+	Format        string     `query:"format"`
+	CreatedAfter  *time.Time `query:"created_after"`
+	CreatedBefore *time.Time `query:"created_before"`
+}
+
+// exportWriter writes exported examples in a format.
+type exportWriter interface {
+	contentType() string
+	writeHeader() error
+	write(example *entities_example_v1.Example) error
+	flush() error
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonWriter) contentType() string {
+	return "application/x-ndjson"
+}
+
+func (w *ndjsonWriter) writeHeader() error {
+	return nil
+}
+
+func (w *ndjsonWriter) write(example *entities_example_v1.Example) error {
+	return w.encoder.Encode(dto_example_v1.FromEntity(example))
+}
+
+func (w *ndjsonWriter) flush() error {
+	return nil
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvWriter) contentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (w *csvWriter) writeHeader() error {
+	return w.writer.Write([]string{"id", "description", "created_at", "updated_at"})
+}
+
+func (w *csvWriter) write(example *entities_example_v1.Example) error {
+	return w.writer.Write([]string{
+		example.ID,
+		example.Description,
+		example.CreatedAt.Format(time.RFC3339Nano),
+		example.UpdatedAt.Format(time.RFC3339Nano),
+	})
+}
+
+func (w *csvWriter) flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// ExportExamples streams the examples as they are read, so that exports use a
+// constant amount of memory whatever the number of examples.
+func (h *Handler) ExportExamples(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req ExportExamplesRequest
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("handlers.http.private.example.v1.export_examples.Handler.ExportExamples: can not bind request")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	var writer exportWriter
+	switch req.Format {
+	case "", ExportFormatNDJSON:
+		req.Format = ExportFormatNDJSON
+		writer = &ndjsonWriter{encoder: json.NewEncoder(c.Response())}
+	case ExportFormatCSV:
+		writer = &csvWriter{writer: csv.NewWriter(c.Response())}
+	default:
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	var filter entities_example_v1.ExportFilter
+	if req.CreatedAfter != nil {
+		filter.CreatedAfter = *req.CreatedAfter
+	}
+	if req.CreatedBefore != nil {
+		filter.CreatedBefore = *req.CreatedBefore
+	}
+
+	// the response starts with the first example, so that errors occurring
+	// before it are still answered with a proper status
+	written := 0
+	start := func() error {
+		c.Response().Header().Set(echo.HeaderContentType, writer.contentType())
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="examples.%s"`, req.Format))
+		c.Response().WriteHeader(http.StatusOK)
+
+		return writer.writeHeader()
+	}
+	flush := func() error {
+		if err := writer.flush(); err != nil {
+			return err
+		}
+		c.Response().Flush()
+
+		return nil
+	}
+
+	err := h.service.ExportExamples(ctx, filter, func(example *entities_example_v1.Example) error {
+		if written == 0 {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		if err := writer.write(example); err != nil {
+			return err
+		}
+
+		written++
+		if written%exportFlushInterval == 0 {
+			return flush()
+		}
+
+		return nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			log.Info().
+				Int("written", written).
+				Msg("handlers.http.private.example.v1.export_examples.Handler.ExportExamples: export canceled by the client")
+			return nil
+		}
+
+		if written == 0 {
+			return c.JSON(pkg_http.TranslateError(ctx, err))
+		}
+
+		// the status is already sent, the truncated export can only be logged
+		log.Error().Err(err).
+			Int("written", written).
+			Msg("handlers.http.private.example.v1.export_examples.Handler.ExportExamples: export interrupted")
+		return nil
+	}
+
+	if written == 0 {
+		if err := start(); err != nil {
+			return err
+		}
+	}
+
+	return flush()
+}
@@ -4,34 +4,53 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
-	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	"github.com/rs/zerolog/log"
+	dto_example_v1 "github.com/teyz/go-svc-template/internal/dto/example/v1"
 	pkg_http "github.com/teyz/go-svc-template/pkg/http"
 )
 
+type FetchExamplesRequest struct {
+	IncludeDeleted bool   `query:"include_deleted"`
+	Fields         string `query:"fields"`
+}
+
 type FetchExamplesResponse struct {
-	Examples []*entities_example_v1.Example `json:"examples"`
+	Examples []*dto_example_v1.Example `json:"examples"`
+}
+
+// FetchSparseExamplesResponse holds the examples restricted to the fields requested.
+type FetchSparseExamplesResponse struct {
+	Examples []map[string]interface{} `json:"examples"`
 }
 
 func (h *Handler) FetchExamples(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	examples, err := h.service.FetchExamples(ctx)
+	var req FetchExamplesRequest
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("handlers.http.private.example.v1.fetch_examples.Handler.FetchExamples: can not bind request")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	fields := parseFields(req.Fields)
+
+	examples, err := h.service.FetchExamples(ctx, req.IncludeDeleted, fields)
 	if err != nil {
 		return c.JSON(pkg_http.TranslateError(ctx, err))
 	}
 
-	examplesResp := make([]*entities_example_v1.Example, 0, len(examples))
+	if len(fields) > 0 {
+		sparseExamples := make([]map[string]interface{}, 0, len(examples))
+		for _, example := range examples {
+			sparseExamples = append(sparseExamples, dto_example_v1.Sparse(example, fields))
+		}
 
-	for _, example := range examples {
-		examplesResp = append(examplesResp, &entities_example_v1.Example{
-			ID:          example.ID,
-			Description: example.Description,
-			CreatedAt:   example.CreatedAt,
-			UpdatedAt:   example.UpdatedAt,
-		})
+		return c.JSON(http.StatusOK, pkg_http.NewHTTPResponse(http.StatusOK, pkg_http.MessageSuccess, FetchSparseExamplesResponse{
+			Examples: sparseExamples,
+		}))
 	}
 
 	return c.JSON(http.StatusOK, pkg_http.NewHTTPResponse(http.StatusOK, pkg_http.MessageSuccess, FetchExamplesResponse{
-		Examples: examplesResp,
+		Examples: dto_example_v1.FromEntities(examples),
 	}))
 }
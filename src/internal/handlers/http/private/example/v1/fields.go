@@ -0,0 +1,17 @@
+package handlers_http_private_example_v1
+
+import "strings"
+
+// parseFields splits the comma-separated fields query parameter, an empty one
+// selecting every field.
+func parseFields(raw string) []string {
+	fields := make([]string, 0)
+
+	for _, field := range strings.Split(raw, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
@@ -5,12 +5,21 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
-	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	dto_example_v1 "github.com/teyz/go-svc-template/internal/dto/example/v1"
 	pkg_http "github.com/teyz/go-svc-template/pkg/http"
 )
 
+type GetExampleByIDRequest struct {
+	Fields string `query:"fields"`
+}
+
 type GetExampleByIDResponse struct {
-	Example *entities_example_v1.Example `json:"example"`
+	Example *dto_example_v1.Example `json:"example"`
+}
+
+// GetSparseExampleByIDResponse holds the example restricted to the fields requested.
+type GetSparseExampleByIDResponse struct {
+	Example map[string]interface{} `json:"example"`
 }
 
 func (h *Handler) GetExampleByID(c echo.Context) error {
@@ -22,17 +31,26 @@ func (h *Handler) GetExampleByID(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
 	}
 
-	example, err := h.service.GetExampleByID(ctx, id)
+	var req GetExampleByIDRequest
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("handlers.http.private.example.v1.get_example.Handler.GetExampleByID: can not bind request")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	fields := parseFields(req.Fields)
+
+	example, err := h.service.GetExampleByID(ctx, id, fields)
 	if err != nil {
 		return c.JSON(pkg_http.TranslateError(ctx, err))
 	}
 
+	if len(fields) > 0 {
+		return c.JSON(http.StatusOK, pkg_http.NewHTTPResponse(http.StatusOK, pkg_http.MessageSuccess, GetSparseExampleByIDResponse{
+			Example: dto_example_v1.Sparse(example, fields),
+		}))
+	}
+
 	return c.JSON(http.StatusOK, pkg_http.NewHTTPResponse(http.StatusOK, pkg_http.MessageSuccess, GetExampleByIDResponse{
-		Example: &entities_example_v1.Example{
-			ID:          example.ID,
-			Description: example.Description,
-			CreatedAt:   example.CreatedAt,
-			UpdatedAt:   example.UpdatedAt,
-		},
+		Example: dto_example_v1.FromEntity(example),
 	}))
 }
@@ -0,0 +1,33 @@
+package handlers_http_private_example_v1
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	dto_example_v1 "github.com/teyz/go-svc-template/internal/dto/example/v1"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+type RestoreExampleResponse struct {
+	Example *dto_example_v1.Example `json:"example"`
+}
+
+func (h *Handler) RestoreExample(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id := c.Param("id")
+	if id == "" {
+		log.Error().Msg("handlers.http.private.example.v1.restore_example.Handler.RestoreExample: can not get id from context")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	example, err := h.service.RestoreExample(ctx, id)
+	if err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	return c.JSON(http.StatusOK, pkg_http.NewHTTPResponse(http.StatusOK, pkg_http.MessageSuccess, RestoreExampleResponse{
+		Example: dto_example_v1.FromEntity(example),
+	}))
+}
@@ -0,0 +1,45 @@
+package handlers_http_private_example_v1
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	dto_example_v1 "github.com/teyz/go-svc-template/internal/dto/example/v1"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+type SearchExamplesRequest struct {
+	Query  string `query:"q"`
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit"`
+}
+
+type SearchExamplesResponse struct {
+	Results    []*dto_example_v1.SearchResult `json:"results"`
+	NextCursor string                         `json:"next_cursor,omitempty"`
+}
+
+func (h *Handler) SearchExamples(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req SearchExamplesRequest
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("handlers.http.private.example.v1.search_examples.Handler.SearchExamples: can not bind request")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	if req.Query == "" || req.Limit < 0 {
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	results, nextCursor, err := h.service.SearchExamples(ctx, req.Query, req.Cursor, req.Limit)
+	if err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	return c.JSON(http.StatusOK, pkg_http.NewHTTPResponse(http.StatusOK, pkg_http.MessageSuccess, SearchExamplesResponse{
+		Results:    dto_example_v1.SearchResultsFromEntities(results),
+		NextCursor: nextCursor,
+	}))
+}
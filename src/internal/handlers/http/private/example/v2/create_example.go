@@ -0,0 +1,35 @@
+package handlers_http_private_example_v2
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	dto_example_v2 "github.com/teyz/go-svc-template/internal/dto/example/v2"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+type CreateExampleRequest struct {
+	Description string `json:"description"`
+}
+
+func (h *Handler) CreateExample(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req CreateExampleRequest
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("handlers.http.private.example.v2.create_example.Handler.CreateExample: can not bind request")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	if req.Description == "" {
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	example, err := h.service.CreateExample(ctx, req.Description)
+	if err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	return c.JSON(http.StatusCreated, pkg_http.NewHTTPResponse(http.StatusCreated, pkg_http.MessageSuccess, dto_example_v2.FromEntity(example)))
+}
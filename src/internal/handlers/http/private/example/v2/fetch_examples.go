@@ -0,0 +1,31 @@
+package handlers_http_private_example_v2
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	dto_example_v2 "github.com/teyz/go-svc-template/internal/dto/example/v2"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+type FetchExamplesRequest struct {
+	IncludeDeleted bool `query:"include_deleted"`
+}
+
+func (h *Handler) FetchExamples(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req FetchExamplesRequest
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("handlers.http.private.example.v2.fetch_examples.Handler.FetchExamples: can not bind request")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	examples, err := h.service.FetchExamples(ctx, req.IncludeDeleted, nil)
+	if err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	return c.JSON(http.StatusOK, pkg_http.NewHTTPResponse(http.StatusOK, pkg_http.MessageSuccess, dto_example_v2.ListFromEntities(examples)))
+}
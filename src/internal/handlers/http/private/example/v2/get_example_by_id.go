@@ -0,0 +1,27 @@
+package handlers_http_private_example_v2
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	dto_example_v2 "github.com/teyz/go-svc-template/internal/dto/example/v2"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+func (h *Handler) GetExampleByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id := c.Param("id")
+	if id == "" {
+		log.Error().Msg("handlers.http.private.example.v2.get_example_by_id.Handler.GetExampleByID: can not get id from context")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	example, err := h.service.GetExampleByID(ctx, id, nil)
+	if err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	return c.JSON(http.StatusOK, pkg_http.NewHTTPResponse(http.StatusOK, pkg_http.MessageSuccess, dto_example_v2.FromEntity(example)))
+}
@@ -0,0 +1,19 @@
+package handlers_http_private_example_v2
+
+import (
+	"context"
+
+	service_v1 "github.com/teyz/go-svc-template/internal/service/v1"
+)
+
+// Handler serves the v2 example routes, from the same service as v1 but with
+// the v2 representation of examples.
+type Handler struct {
+	service service_v1.ExampleStoreService
+}
+
+func NewHandler(_ context.Context, service service_v1.ExampleStoreService) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
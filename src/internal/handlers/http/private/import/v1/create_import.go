@@ -0,0 +1,64 @@
+package handlers_http_private_import_v1
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	entities_import_v1 "github.com/teyz/go-svc-template/internal/entities/import/v1"
+	service_v1 "github.com/teyz/go-svc-template/internal/service/v1"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+type CreateImportResponse struct {
+	Import *entities_import_v1.Import `json:"import"`
+}
+
+// CreateImport accepts a multipart upload of a CSV or NDJSON file in the file
+// field. The format is read from the format field, or else from the extension
+// of the file. The file is processed in the background.
+func (h *Handler) CreateImport(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		log.Error().Err(err).Msg("handlers.http.private.import.v1.create_import.Handler.CreateImport: can not get file from request")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	if file.Size > service_v1.ImportMaxBytes {
+		return c.JSON(http.StatusRequestEntityTooLarge, pkg_http.NewHTTPResponse(http.StatusRequestEntityTooLarge, pkg_http.MessageRequestTooLargeError, nil))
+	}
+
+	format := c.FormValue("format")
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file.Filename)), ".")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		log.Error().Err(err).Msg("handlers.http.private.import.v1.create_import.Handler.CreateImport: can not open file")
+		return c.JSON(http.StatusInternalServerError, pkg_http.NewHTTPResponse(http.StatusInternalServerError, pkg_http.MessageInternalServerError, nil))
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, service_v1.ImportMaxBytes+1))
+	if err != nil {
+		log.Error().Err(err).Msg("handlers.http.private.import.v1.create_import.Handler.CreateImport: can not read file")
+		return c.JSON(http.StatusInternalServerError, pkg_http.NewHTTPResponse(http.StatusInternalServerError, pkg_http.MessageInternalServerError, nil))
+	}
+
+	imp, err := h.service.CreateImport(ctx, format, data)
+	if err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, "/private/v1/imports/"+imp.ID)
+
+	return c.JSON(http.StatusAccepted, pkg_http.NewHTTPResponse(http.StatusAccepted, pkg_http.MessageSuccess, CreateImportResponse{
+		Import: imp,
+	}))
+}
@@ -0,0 +1,45 @@
+package handlers_http_private_import_v1
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+// FetchImportErrors downloads the rows rejected by an import as a CSV report.
+func (h *Handler) FetchImportErrors(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id := c.Param("id")
+	if id == "" {
+		log.Error().Msg("handlers.http.private.import.v1.fetch_import_errors.Handler.FetchImportErrors: can not get id from context")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	rowErrors, err := h.service.FetchImportErrors(ctx, id)
+	if err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s-errors.csv"`, id))
+	c.Response().WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(c.Response())
+	if err := writer.Write([]string{"row", "message"}); err != nil {
+		return err
+	}
+	for _, rowError := range rowErrors {
+		if err := writer.Write([]string{strconv.Itoa(rowError.Row), rowError.Message}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
@@ -0,0 +1,33 @@
+package handlers_http_private_import_v1
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	entities_import_v1 "github.com/teyz/go-svc-template/internal/entities/import/v1"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+type GetImportResponse struct {
+	Import *entities_import_v1.Import `json:"import"`
+}
+
+func (h *Handler) GetImport(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id := c.Param("id")
+	if id == "" {
+		log.Error().Msg("handlers.http.private.import.v1.get_import.Handler.GetImport: can not get id from context")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	imp, err := h.service.GetImport(ctx, id)
+	if err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	return c.JSON(http.StatusOK, pkg_http.NewHTTPResponse(http.StatusOK, pkg_http.MessageSuccess, GetImportResponse{
+		Import: imp,
+	}))
+}
@@ -0,0 +1,30 @@
+package handlers_http_public_example_v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	dto_example_v1 "github.com/teyz/go-svc-template/internal/dto/example/v1"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+type FetchExamplesResponse struct {
+	Examples []*dto_example_v1.Example `json:"examples"`
+}
+
+// FetchExamples lists the examples without Last-Modified, as the most recent
+// update does not change when an example is deleted. Revalidation relies on
+// the ETag.
+func (h *Handler) FetchExamples(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	examples, err := h.service.FetchExamples(ctx, false, nil)
+	if err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	return pkg_http.CacheableJSON(c, http.StatusOK, pkg_http.NewHTTPResponse(http.StatusOK, pkg_http.MessageSuccess, FetchExamplesResponse{
+		Examples: dto_example_v1.FromEntities(examples),
+	}), time.Time{})
+}
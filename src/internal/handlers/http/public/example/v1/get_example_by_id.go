@@ -0,0 +1,33 @@
+package handlers_http_public_example_v1
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	dto_example_v1 "github.com/teyz/go-svc-template/internal/dto/example/v1"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+type GetExampleByIDResponse struct {
+	Example *dto_example_v1.Example `json:"example"`
+}
+
+func (h *Handler) GetExampleByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id := c.Param("id")
+	if id == "" {
+		log.Error().Msg("handlers.http.public.example.v1.get_example_by_id.Handler.GetExampleByID: can not get id from context")
+		return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+	}
+
+	example, err := h.service.GetExampleByID(ctx, id, nil)
+	if err != nil {
+		return c.JSON(pkg_http.TranslateError(ctx, err))
+	}
+
+	return pkg_http.CacheableJSON(c, http.StatusOK, pkg_http.NewHTTPResponse(http.StatusOK, pkg_http.MessageSuccess, GetExampleByIDResponse{
+		Example: dto_example_v1.FromEntity(example),
+	}), example.UpdatedAt)
+}
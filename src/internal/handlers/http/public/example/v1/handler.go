@@ -0,0 +1,19 @@
+package handlers_http_public_example_v1
+
+import (
+	"context"
+
+	service_v1 "github.com/teyz/go-svc-template/internal/service/v1"
+)
+
+// Handler serves the read-only public example routes, whose responses can be
+// cached by CDNs.
+type Handler struct {
+	service service_v1.ExampleStoreService
+}
+
+func NewHandler(_ context.Context, service service_v1.ExampleStoreService) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
@@ -3,6 +3,8 @@ package handlers_http
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -10,22 +12,47 @@ import (
 
 	"github.com/teyz/go-svc-template/internal/handlers"
 	handlers_http_private_health_v1 "github.com/teyz/go-svc-template/internal/handlers/http/health/v1"
+	handlers_http_private_apikey_v1 "github.com/teyz/go-svc-template/internal/handlers/http/private/apikey/v1"
+	handlers_http_private_audit_v1 "github.com/teyz/go-svc-template/internal/handlers/http/private/audit/v1"
 	handlers_http_private_example_v1 "github.com/teyz/go-svc-template/internal/handlers/http/private/example/v1"
+	handlers_http_private_example_v2 "github.com/teyz/go-svc-template/internal/handlers/http/private/example/v2"
+	handlers_http_private_import_v1 "github.com/teyz/go-svc-template/internal/handlers/http/private/import/v1"
+	handlers_http_public_example_v1 "github.com/teyz/go-svc-template/internal/handlers/http/public/example/v1"
 	service_v1 "github.com/teyz/go-svc-template/internal/service/v1"
+	pkg_audit "github.com/teyz/go-svc-template/pkg/audit"
+	pkg_auth "github.com/teyz/go-svc-template/pkg/auth"
+	pkg_featureflags "github.com/teyz/go-svc-template/pkg/featureflags"
 	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+	pkg_ratelimit "github.com/teyz/go-svc-template/pkg/ratelimit"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
 )
 
 type httpServer struct {
-	router  *echo.Echo
-	config  pkg_http.HTTPServerConfig
-	service service_v1.ExampleStoreService
+	router         *echo.Echo
+	config         pkg_http.HTTPServerConfig
+	service        service_v1.Service
+	featureFlags   *pkg_featureflags.Client
+	authenticators []pkg_auth.Authenticator
+	rateLimiter    *pkg_ratelimit.Limiter
+	tenancy        pkg_tenant.TenancyConfig
 }
 
-func NewServer(ctx context.Context, cfg pkg_http.HTTPServerConfig, service service_v1.ExampleStoreService) (handlers.Server, error) {
+// NewServer returns the HTTP server, private endpoints are left unauthenticated
+// when no authenticator is given and unlimited when rateLimiter is nil.
+func NewServer(ctx context.Context, cfg pkg_http.HTTPServerConfig, service service_v1.Service, featureFlags *pkg_featureflags.Client, authenticators []pkg_auth.Authenticator, rateLimiter *pkg_ratelimit.Limiter, tenancy pkg_tenant.TenancyConfig) (handlers.Server, error) {
+	router := echo.New()
+	// client IPs identify unauthenticated callers, they are only read from
+	// headers set by trusted proxies
+	router.IPExtractor = cfg.IPExtractor()
+
 	return &httpServer{
-		router:  echo.New(),
-		config:  cfg,
-		service: service,
+		router:         router,
+		config:         cfg,
+		service:        service,
+		featureFlags:   featureFlags,
+		authenticators: authenticators,
+		rateLimiter:    rateLimiter,
+		tenancy:        tenancy,
 	}, nil
 }
 
@@ -36,6 +63,11 @@ func (s *httpServer) Setup(ctx context.Context) error {
 	// setup handlers
 	privateHealthV1Handlers := handlers_http_private_health_v1.NewHandler(ctx)
 	privateExampleV1Handlers := handlers_http_private_example_v1.NewHandler(ctx, s.service)
+	privateExampleV2Handlers := handlers_http_private_example_v2.NewHandler(ctx, s.service)
+	privateAPIKeyV1Handlers := handlers_http_private_apikey_v1.NewHandler(ctx, s.service)
+	privateAuditV1Handlers := handlers_http_private_audit_v1.NewHandler(ctx, s.service)
+	privateImportV1Handlers := handlers_http_private_import_v1.NewHandler(ctx, s.service)
+	publicExampleV1Handlers := handlers_http_public_example_v1.NewHandler(ctx, s.service)
 
 	// setup middlewares
 	s.router.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
@@ -51,31 +83,184 @@ func (s *httpServer) Setup(ctx context.Context) error {
 		},
 	}))
 	s.router.Use(middleware.Recover())
-	s.router.Use(middleware.CORS())
+	s.router.Use(middleware.RequestID())
+	s.router.Use(pkg_http.ClearWriteDeadline(func(c echo.Context) bool {
+		return c.Path() == exportPath
+	}))
+	s.router.Use(pkg_http.SecurityHeaders(&s.config))
+	s.router.Use(pkg_http.BodyLimit(&s.config, func(c echo.Context) bool {
+		return c.Path() == uploadPath
+	}))
+	// public endpoints have their own CORS policy, applied at the root so that
+	// it also answers preflight requests. Without allowed origins, cross-origin
+	// requests are left to the browser, echo allowing every origin otherwise.
+	if len(s.config.CORSAllowOrigins) > 0 {
+		s.router.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+			Skipper:      isPublicPath,
+			AllowOrigins: s.config.CORSAllowOrigins,
+			AllowMethods: s.config.CORSAllowMethods,
+			AllowHeaders: s.config.CORSAllowHeaders,
+		}))
+	}
+	s.router.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		Skipper: func(c echo.Context) bool {
+			return !isPublicPath(c)
+		},
+		AllowOrigins:  s.config.Public.CORSAllowOrigins,
+		AllowMethods:  []string{http.MethodGet, http.MethodHead},
+		AllowHeaders:  []string{pkg_http.HeaderIfNoneMatch, pkg_http.HeaderIfModifiedSince},
+		ExposeHeaders: []string{pkg_http.HeaderETag, echo.HeaderLastModified},
+	}))
+	s.router.Use(middleware.GzipWithConfig(middleware.GzipConfig{
+		MinLength: s.config.GzipMinLength,
+	}))
 
 	s.router.Pre(middleware.RemoveTrailingSlash())
 
 	// health endpoints
 	s.router.GET("/health", privateHealthV1Handlers.HealthCheck)
 
-	// private endpoints
-	privateV1 := s.router.Group("/private/v1")
+	// public endpoints, read-only and cacheable by CDNs
+	publicV1 := s.router.Group("/public/v1", s.rateLimit("public"), pkg_tenant.PublicMiddleware(&s.tenancy), pkg_featureflags.Middleware(s.featureFlags), pkg_http.CacheControl(s.config.Public.CacheMaxAge, s.config.Public.CacheSharedMaxAge))
+	publicV1.GET("/examples", publicExampleV1Handlers.FetchExamples)
+	publicV1.GET("/examples/:id", publicExampleV1Handlers.GetExampleByID)
+
+	// private endpoints, every version being served by the same service
+	if len(s.authenticators) == 0 {
+		log.Warn().
+			Msg("handlers.http.httpServer.Setup: authentication is disabled on private endpoints")
+	}
+	privateV1 := s.privateGroup(privateV1Path)
+	privateV2 := s.privateGroup("/private/v2")
 
 	// example endpoints
-	examplesV1 := privateV1.Group("/examples")
-	examplesV1.GET("", privateExampleV1Handlers.FetchExamples)
-	examplesV1.POST("", privateExampleV1Handlers.CreateExample)
-	examplesV1.GET("/:id", privateExampleV1Handlers.GetExampleByID)
+	examplesV1 := privateV1.Group(examplesPath, s.rateLimit("examples"))
+	examplesV1.GET("", privateExampleV1Handlers.FetchExamples, s.requireScopes(service_v1.ScopeExamplesRead))
+	examplesV1.POST("", privateExampleV1Handlers.CreateExample, s.requireScopes(service_v1.ScopeExamplesWrite))
+	// the colon of the batch routes is escaped not to be read as a path parameter
+	examplesV1.POST("\\:batch", privateExampleV1Handlers.CreateExamples, s.requireScopes(service_v1.ScopeExamplesWrite))
+	examplesV1.PATCH("\\:batch", privateExampleV1Handlers.UpdateExamples, s.requireScopes(service_v1.ScopeExamplesWrite))
+	examplesV1.DELETE("\\:batch", privateExampleV1Handlers.DeleteExamples, s.requireScopes(service_v1.ScopeExamplesWrite))
+	examplesV1.GET(exportRoute, privateExampleV1Handlers.ExportExamples, s.requireScopes(service_v1.ScopeExamplesRead))
+	examplesV1.POST(uploadRoute, privateImportV1Handlers.CreateImport, s.requireScopes(service_v1.ScopeExamplesWrite), middleware.BodyLimit(s.config.UploadBodyLimit))
+	examplesV1.GET("/search", privateExampleV1Handlers.SearchExamples, s.requireScopes(service_v1.ScopeExamplesRead))
+	examplesV1.GET("/:id", privateExampleV1Handlers.GetExampleByID, s.requireScopes(service_v1.ScopeExamplesRead))
+	examplesV1.DELETE("/:id", privateExampleV1Handlers.DeleteExample, s.requireScopes(service_v1.ScopeExamplesWrite))
+	examplesV1.POST("/:id/restore", privateExampleV1Handlers.RestoreExample, s.requireScopes(service_v1.ScopeExamplesWrite))
+
+	// v2 example endpoints
+	examplesV2 := privateV2.Group("/examples", s.rateLimit("examples"))
+	examplesV2.GET("", privateExampleV2Handlers.FetchExamples, s.requireScopes(service_v1.ScopeExamplesRead))
+	examplesV2.POST("", privateExampleV2Handlers.CreateExample, s.requireScopes(service_v1.ScopeExamplesWrite))
+	examplesV2.GET("/:id", privateExampleV2Handlers.GetExampleByID, s.requireScopes(service_v1.ScopeExamplesRead))
+
+	// api key endpoints
+	apiKeysV1 := privateV1.Group("/api-keys", s.rateLimit("api-keys"), s.requireScopes(service_v1.ScopeAPIKeysAdmin))
+	apiKeysV1.GET("", privateAPIKeyV1Handlers.FetchAPIKeys)
+	apiKeysV1.POST("", privateAPIKeyV1Handlers.CreateAPIKey)
+	apiKeysV1.DELETE("/:id", privateAPIKeyV1Handlers.RevokeAPIKey)
+
+	// audit endpoints
+	auditV1 := privateV1.Group("/audit", s.rateLimit("audit"), s.requireScopes(service_v1.ScopeAuditRead))
+	auditV1.GET("", privateAuditV1Handlers.FetchAuditEntries)
+
+	// import endpoints
+	importsV1 := privateV1.Group("/imports", s.rateLimit("imports"), s.requireScopes(service_v1.ScopeExamplesWrite))
+	importsV1.GET("/:id", privateImportV1Handlers.GetImport)
+	importsV1.GET("/:id/errors", privateImportV1Handlers.FetchImportErrors)
 
 	return nil
 }
 
+const (
+	privateV1Path = "/private/v1"
+	examplesPath  = "/examples"
+
+	// routes with their own limits, registered from the same constants as the
+	// paths their middlewares match
+	exportRoute = "/export"
+	uploadRoute = "/import"
+	exportPath  = privateV1Path + examplesPath + exportRoute
+	uploadPath  = privateV1Path + examplesPath + uploadRoute
+)
+
+func isPublicPath(c echo.Context) bool {
+	return strings.HasPrefix(c.Request().URL.Path, "/public/")
+}
+
+// privateGroup returns a group of private endpoints, authenticating callers and
+// resolving their tenant and audit metadata.
+func (s *httpServer) privateGroup(prefix string) *echo.Group {
+	group := s.router.Group(prefix)
+	if len(s.authenticators) > 0 {
+		group.Use(pkg_auth.Middleware(s.authenticators...))
+	}
+	group.Use(pkg_tenant.Middleware(&s.tenancy))
+	group.Use(pkg_audit.Middleware())
+	// flags are evaluated for the authenticated caller
+	group.Use(pkg_featureflags.Middleware(s.featureFlags))
+
+	return group
+}
+
+// requireScopes enforces the scopes of a route, unless authentication is disabled.
+func (s *httpServer) requireScopes(scopes ...string) echo.MiddlewareFunc {
+	if len(s.authenticators) == 0 {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return next
+		}
+	}
+
+	return pkg_auth.RequireScopes(scopes...)
+}
+
+// rateLimit limits the requests of every caller on a route group, unless no limiter is set.
+func (s *httpServer) rateLimit(group string) echo.MiddlewareFunc {
+	if s.rateLimiter == nil {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return next
+		}
+	}
+
+	return pkg_ratelimit.Middleware(s.rateLimiter, group)
+}
+
 func (s *httpServer) Start(ctx context.Context) error {
 	log.Info().
 		Uint16("port", s.config.Port).
 		Msg("handlers.http.httpServer.Start: Starting HTTP server...")
 
-	return s.router.Start(fmt.Sprintf(":%d", s.config.Port))
+	s.router.Server.ReadTimeout = s.config.ReadTimeout
+	s.router.Server.ReadHeaderTimeout = s.config.ReadHeaderTimeout
+	s.router.Server.WriteTimeout = s.config.WriteTimeout
+	s.router.Server.IdleTimeout = s.config.IdleTimeout
+
+	if !s.config.TLS.Enabled() {
+		return s.router.Start(fmt.Sprintf(":%d", s.config.Port))
+	}
+
+	certificates, err := pkg_http.NewCertificates(s.config.TLS)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := certificates.Watch(ctx); err != nil {
+			log.Error().Err(err).
+				Msg("handlers.http.httpServer.Start: unable to watch certificates")
+		}
+	}()
+
+	log.Info().
+		Str("min_version", s.config.TLS.MinVersion).
+		Bool("client_auth", s.config.TLS.ClientCAFile != "").
+		Msg("handlers.http.httpServer.Start: serving HTTPS and HTTP/2")
+
+	// HTTP/2 is negotiated by the tls configuration
+	s.router.Server.Addr = fmt.Sprintf(":%d", s.config.Port)
+	s.router.Server.TLSConfig = certificates.TLSConfig()
+
+	return s.router.StartServer(s.router.Server)
 }
 
 func (s *httpServer) Stop(ctx context.Context) error {
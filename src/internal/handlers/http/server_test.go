@@ -0,0 +1,50 @@
+package handlers_http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+	pkg_ratelimit "github.com/teyz/go-svc-template/pkg/ratelimit"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
+)
+
+func Test_Setup(t *testing.T) {
+	t.Run("ok - routes with their own limits are registered", func(t *testing.T) {
+		server, err := NewServer(context.Background(), pkg_http.HTTPServerConfig{BodyLimit: "1M", UploadBodyLimit: "11M"}, nil, nil, nil, nil, pkg_tenant.TenancyConfig{})
+		assert.NoError(t, err)
+		assert.NoError(t, server.Setup(context.Background()))
+
+		routes := make(map[string]bool)
+		for _, route := range server.(*httpServer).router.Routes() {
+			routes[route.Method+" "+route.Path] = true
+		}
+
+		assert.True(t, routes[http.MethodGet+" "+exportPath])
+		assert.True(t, routes[http.MethodPost+" "+uploadPath])
+	})
+}
+
+func Test_NewServer(t *testing.T) {
+	t.Run("ok - spoofed forwarded IPs do not change the rate limit key", func(t *testing.T) {
+		server, err := NewServer(context.Background(), pkg_http.HTTPServerConfig{}, nil, nil, nil, nil, pkg_tenant.TenancyConfig{})
+		assert.NoError(t, err)
+		router := server.(*httpServer).router
+
+		identities := make(map[string]bool)
+		for _, forwarded := range []string{"198.51.100.1", "198.51.100.2"} {
+			req := httptest.NewRequest(http.MethodGet, "/public/v1/examples", nil)
+			req.RemoteAddr = "203.0.113.1:1234"
+			req.Header.Set(echo.HeaderXForwardedFor, forwarded)
+			req.Header.Set(echo.HeaderXRealIP, forwarded)
+
+			identities[pkg_ratelimit.Identity(router.NewContext(req, httptest.NewRecorder()))] = true
+		}
+
+		assert.Equal(t, map[string]bool{"203.0.113.1": true}, identities)
+	})
+}
@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ImportProcessor processes the next pending import, if any.
+type ImportProcessor interface {
+	ProcessNextImport(ctx context.Context) (bool, error)
+}
+
+// ImportExamples processes the pending imports one after the other, polling
+// for new ones every interval once there are none left.
+type ImportExamples struct {
+	processor ImportProcessor
+	interval  time.Duration
+}
+
+func NewImportExamples(processor ImportProcessor, interval time.Duration) *ImportExamples {
+	return &ImportExamples{
+		processor: processor,
+		interval:  interval,
+	}
+}
+
+// Run processes imports until ctx is done.
+func (j *ImportExamples) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain processes imports until there are none left or one fails.
+func (j *ImportExamples) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := j.processor.ProcessNextImport(ctx)
+		if err != nil {
+			log.Error().Err(err).
+				Msg("jobs.ImportExamples.drain: unable to process import")
+			return
+		}
+
+		if !processed {
+			return
+		}
+	}
+}
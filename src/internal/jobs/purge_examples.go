@@ -0,0 +1,89 @@
+package jobs
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// purgeBatchSize bounds how many examples are deleted by a single statement,
+// so that a large backlog does not hold locks and grow the WAL at once.
+const purgeBatchSize = 1000
+
+// ExamplePurger hard-deletes up to limit examples soft-deleted before a given
+// time.
+type ExamplePurger interface {
+	PurgeDeletedExamples(ctx context.Context, deletedBefore time.Time, limit int) (int, error)
+}
+
+// PurgeExamples periodically purges the examples soft-deleted for longer than
+// the retention, by batches of purgeBatchSize.
+type PurgeExamples struct {
+	purger    ExamplePurger
+	interval  time.Duration
+	batchSize int
+	retention atomic.Int64
+}
+
+func NewPurgeExamples(purger ExamplePurger, interval time.Duration, retention time.Duration) *PurgeExamples {
+	j := &PurgeExamples{
+		purger:    purger,
+		interval:  interval,
+		batchSize: purgeBatchSize,
+	}
+	j.retention.Store(int64(retention))
+
+	return j
+}
+
+// SetRetention changes how long deleted examples are kept, it is safe to call while running.
+func (j *PurgeExamples) SetRetention(retention time.Duration) {
+	j.retention.Store(int64(retention))
+}
+
+// Run purges deleted examples every interval until ctx is done.
+func (j *PurgeExamples) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.purge(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge deletes batches of examples until a batch is not full, ctx is done or
+// one fails.
+func (j *PurgeExamples) purge(ctx context.Context, now time.Time) {
+	deletedBefore := now.Add(-time.Duration(j.retention.Load()))
+
+	purged := 0
+	for ctx.Err() == nil {
+		count, err := j.purger.PurgeDeletedExamples(ctx, deletedBefore, j.batchSize)
+		purged += count
+		if err != nil {
+			log.Error().Err(err).
+				Int("purged", purged).
+				Msg("jobs.PurgeExamples.purge: unable to purge deleted examples")
+			return
+		}
+
+		if count < j.batchSize {
+			break
+		}
+	}
+
+	if purged > 0 {
+		log.Info().
+			Int("purged", purged).
+			Time("deleted_before", deletedBefore).
+			Msg("jobs.PurgeExamples.purge: purged deleted examples")
+	}
+}
@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePurger returns the counts of batches in order, then err.
+type fakePurger struct {
+	batches []int
+	err     error
+
+	deletedBefore []time.Time
+	limits        []int
+}
+
+func (p *fakePurger) PurgeDeletedExamples(ctx context.Context, deletedBefore time.Time, limit int) (int, error) {
+	p.deletedBefore = append(p.deletedBefore, deletedBefore)
+	p.limits = append(p.limits, limit)
+
+	if len(p.batches) == 0 {
+		return 0, p.err
+	}
+
+	count := p.batches[0]
+	p.batches = p.batches[1:]
+
+	return count, nil
+}
+
+func Test_PurgeExamples_purge(t *testing.T) {
+	now := time.Now()
+
+	t.Run("ok - purge batches until one is not full", func(t *testing.T) {
+		purger := &fakePurger{batches: []int{2, 2, 1, 2}}
+
+		j := NewPurgeExamples(purger, time.Hour, 24*time.Hour)
+		j.batchSize = 2
+		j.purge(context.Background(), now)
+
+		assert.Equal(t, []int{2, 2, 2}, purger.limits)
+		for _, deletedBefore := range purger.deletedBefore {
+			assert.Equal(t, now.Add(-24*time.Hour), deletedBefore)
+		}
+	})
+	t.Run("ok - purge with the current retention", func(t *testing.T) {
+		purger := &fakePurger{}
+
+		j := NewPurgeExamples(purger, time.Hour, 24*time.Hour)
+		j.SetRetention(time.Hour)
+		j.purge(context.Background(), now)
+
+		assert.Equal(t, []time.Time{now.Add(-time.Hour)}, purger.deletedBefore)
+		assert.Equal(t, []int{purgeBatchSize}, purger.limits)
+	})
+	t.Run("nok - stop at the first failing batch", func(t *testing.T) {
+		purger := &fakePurger{batches: []int{2}, err: errors.New("error")}
+
+		j := NewPurgeExamples(purger, time.Hour, 24*time.Hour)
+		j.batchSize = 2
+		j.purge(context.Background(), now)
+
+		assert.Len(t, purger.limits, 2)
+	})
+	t.Run("nok - stop when canceled", func(t *testing.T) {
+		purger := &fakePurger{batches: []int{2, 2, 2}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		j := NewPurgeExamples(purger, time.Hour, 24*time.Hour)
+		j.batchSize = 2
+		j.purge(ctx, now)
+
+		assert.Empty(t, purger.limits)
+	})
+}
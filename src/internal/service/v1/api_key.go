@@ -0,0 +1,175 @@
+package service_v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	entities_apikey_v1 "github.com/teyz/go-svc-template/internal/entities/apikey/v1"
+	entities_audit_v1 "github.com/teyz/go-svc-template/internal/entities/audit/v1"
+	pkg_auth "github.com/teyz/go-svc-template/pkg/auth"
+	"github.com/teyz/go-svc-template/pkg/errors"
+)
+
+const (
+	// apiKeyCacheDuration bounds how long a cached key stays usable when its
+	// revocation could not clear the cache
+	apiKeyCacheDuration = time.Minute * 5
+	// apiKeyLastUsedResolution is how often the last use of a key is written
+	apiKeyLastUsedResolution = time.Minute
+)
+
+func generateAPIKeyCacheKeyWithHash(hash string) string {
+	return fmt.Sprintf("go-svc-template:api_key:hash:%v", hash)
+}
+
+// CreateAPIKey stores a new API key and returns it with the key itself, which
+// cannot be retrieved afterwards.
+func (s *service) CreateAPIKey(ctx context.Context, name string, scopes []string, expiresAt *time.Time) (*entities_apikey_v1.APIKey, string, error) {
+	if err := s.policy(ctx, ScopeAPIKeysAdmin, nil); err != nil {
+		return nil, "", err
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", errors.NewBadRequestError("service.v1.service.CreateAPIKey: expiration must be in the future")
+	}
+
+	key, err := pkg_auth.GenerateAPIKey()
+	if err != nil {
+		log.Error().Err(err).
+			Msg("service.v1.service.CreateAPIKey: unable to generate api key")
+		return nil, "", errors.NewInternalServerError(fmt.Sprintf("service.v1.service.CreateAPIKey: unable to generate api key: %v", err.Error()))
+	}
+
+	var apiKey *entities_apikey_v1.APIKey
+	err = s.store.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		apiKey, err = s.store.CreateAPIKey(ctx, name, key[:pkg_auth.APIKeyDisplayLength], pkg_auth.HashAPIKey(key), scopes, expiresAt)
+		if err != nil {
+			return err
+		}
+
+		return s.recordAudit(ctx, entities_audit_v1.ActionCreate, entities_audit_v1.ResourceTypeAPIKey, apiKey.ID, nil, apiKey)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return apiKey, key, nil
+}
+
+func (s *service) FetchAPIKeys(ctx context.Context) ([]*entities_apikey_v1.APIKey, error) {
+	if err := s.policy(ctx, ScopeAPIKeysAdmin, nil); err != nil {
+		return nil, err
+	}
+
+	return s.store.FetchAPIKeys(ctx)
+}
+
+func (s *service) RevokeAPIKey(ctx context.Context, id string) error {
+	var apiKey *entities_apikey_v1.APIKey
+	err := s.store.WithTx(ctx, func(ctx context.Context) error {
+		before, after, err := s.store.RevokeAPIKey(ctx, id)
+		if err != nil {
+			return err
+		}
+		apiKey = after
+
+		// checked before committing, on the key as it was
+		if err := s.policy(ctx, ScopeAPIKeysAdmin, before); err != nil {
+			return err
+		}
+
+		return s.recordAudit(ctx, entities_audit_v1.ActionUpdate, entities_audit_v1.ResourceTypeAPIKey, apiKey.ID, before, apiKey)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.cache.Del(ctx, generateAPIKeyCacheKeyWithHash(apiKey.Hash))
+	if err != nil {
+		log.Error().Err(err).
+			Str("id", id).
+			Msg("service.v1.service.RevokeAPIKey: unable to remove api key from cache")
+	}
+
+	return nil
+}
+
+// ResolveAPIKey returns the claims granted to key, bound to the tenant of the
+// key. Unknown and revoked keys are rejected with an UnauthorizedError, expired
+// ones with an ExpiredResourceError.
+func (s *service) ResolveAPIKey(ctx context.Context, key string) (*pkg_auth.Claims, error) {
+	hash := pkg_auth.HashAPIKey(key)
+	cacheKey := generateAPIKeyCacheKeyWithHash(hash)
+
+	var apiKey *entities_apikey_v1.APIKey
+
+	cacheAPIKey, err := s.cache.Get(ctx, cacheKey)
+	if err == nil {
+		err = json.Unmarshal([]byte(cacheAPIKey), &apiKey)
+		if err != nil {
+			log.Error().Err(err).
+				Msg("service.v1.service.ResolveAPIKey: unable to unmarshal api key")
+			apiKey = nil
+		}
+	}
+
+	if apiKey == nil {
+		apiKey, err = s.store.GetAPIKeyByHash(ctx, hash)
+		if err != nil {
+			if errors.IsNotFoundError(err) {
+				return nil, errors.NewUnauthorizedError("service.v1.service.ResolveAPIKey: unknown api key")
+			}
+			return nil, err
+		}
+
+		s.cacheAPIKey(ctx, cacheKey, apiKey)
+	}
+
+	now := time.Now()
+
+	if apiKey.RevokedAt != nil {
+		return nil, errors.NewUnauthorizedError(fmt.Sprintf("service.v1.service.ResolveAPIKey: api key %s is revoked", apiKey.ID))
+	}
+
+	if apiKey.IsExpired(now) {
+		return nil, errors.NewExpiredResourceError(fmt.Sprintf("service.v1.service.ResolveAPIKey: api key %s has expired", apiKey.ID))
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyLastUsedResolution {
+		// the touch only succeeds on keys still active, so that a key revoked
+		// since it was loaded is neither accepted nor cached again. Other
+		// failures to record the use must not reject the request.
+		err := s.store.TouchAPIKey(ctx, apiKey.ID, now)
+		switch {
+		case errors.IsNotFoundError(err):
+			return nil, errors.NewUnauthorizedError(fmt.Sprintf("service.v1.service.ResolveAPIKey: api key %s is revoked", apiKey.ID))
+		case err == nil:
+			apiKey.LastUsedAt = &now
+			s.cacheAPIKey(ctx, cacheKey, apiKey)
+		}
+	}
+
+	claims := &pkg_auth.Claims{
+		Scope:    strings.Join(apiKey.Scopes, " "),
+		TenantID: apiKey.TenantID,
+	}
+	claims.Subject = apiKey.ID
+
+	return claims, nil
+}
+
+func (s *service) cacheAPIKey(ctx context.Context, cacheKey string, apiKey *entities_apikey_v1.APIKey) {
+	bytes, err := json.Marshal(apiKey)
+	if err != nil {
+		log.Error().Err(err).
+			Msg("service.v1.service.cacheAPIKey: unable to marshal api key")
+		return
+	}
+
+	s.cache.SetEx(ctx, cacheKey, bytes, apiKeyCacheDuration)
+}
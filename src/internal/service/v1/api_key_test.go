@@ -0,0 +1,232 @@
+package service_v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	database_mocks "github.com/teyz/go-svc-template/internal/database/mocks"
+	entities_apikey_v1 "github.com/teyz/go-svc-template/internal/entities/apikey/v1"
+	pkg_auth "github.com/teyz/go-svc-template/pkg/auth"
+	cache_mocks "github.com/teyz/go-svc-template/pkg/cache/mocks"
+	"github.com/teyz/go-svc-template/pkg/constants"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_CreateAPIKey(t *testing.T) {
+	t.Run("ok - create api key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		apiKeyID := constants.GenerateDataPrefixWithULID(constants.APIKey)
+
+		var hash string
+		mock_database.EXPECT().CreateAPIKey(gomock.Any(), "batch", gomock.Any(), gomock.Any(), []string{"examples:read"}, nil).
+			DoAndReturn(func(ctx context.Context, name string, prefix string, h string, scopes []string, expiresAt *time.Time) (*entities_apikey_v1.APIKey, error) {
+				hash = h
+				return &entities_apikey_v1.APIKey{ID: apiKeyID, Name: name, Prefix: prefix, Hash: h, Scopes: scopes}, nil
+			})
+
+		mock_database.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Return(nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		apiKey, key, err := s.CreateAPIKey(context.Background(), "batch", []string{"examples:read"}, nil)
+		assert.NotNil(t, apiKey)
+		assert.NoError(t, err)
+
+		assert.Equal(t, apiKeyID, apiKey.ID)
+		assert.Equal(t, pkg_auth.HashAPIKey(key), hash)
+		assert.Equal(t, key[:pkg_auth.APIKeyDisplayLength], apiKey.Prefix)
+	})
+	t.Run("nok - create api key expiring in the past", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		expiresAt := time.Now().Add(-time.Hour)
+
+		apiKey, _, err := s.CreateAPIKey(context.Background(), "batch", nil, &expiresAt)
+		assert.Nil(t, apiKey)
+		assert.True(t, errors.IsBadRequestError(err))
+	})
+}
+
+func Test_RevokeAPIKey(t *testing.T) {
+	t.Run("ok - revoke api key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		apiKeyID := constants.GenerateDataPrefixWithULID(constants.APIKey)
+
+		mock_database.EXPECT().RevokeAPIKey(gomock.Any(), apiKeyID).Return(&entities_apikey_v1.APIKey{ID: apiKeyID, Hash: "hash"}, &entities_apikey_v1.APIKey{ID: apiKeyID, Hash: "hash"}, nil)
+		mock_database.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Return(nil)
+		mock_cache.EXPECT().Del(gomock.Any(), "go-svc-template:api_key:hash:hash").Return(nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		assert.NoError(t, s.RevokeAPIKey(context.Background(), apiKeyID))
+	})
+	t.Run("nok - revoke unknown api key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		mock_database.EXPECT().RevokeAPIKey(gomock.Any(), "akey_unknown").Return(nil, nil, errors.NewNotFoundError("error"))
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		assert.True(t, errors.IsNotFoundError(s.RevokeAPIKey(context.Background(), "akey_unknown")))
+	})
+}
+
+func Test_ResolveAPIKey(t *testing.T) {
+	key := "sk_test"
+	hash := pkg_auth.HashAPIKey(key)
+	cacheKey := fmt.Sprintf("go-svc-template:api_key:hash:%v", hash)
+
+	t.Run("ok - resolve api key from cache", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		apiKeyID := constants.GenerateDataPrefixWithULID(constants.APIKey)
+		lastUsedAt := time.Now()
+
+		cached, _ := json.Marshal(&entities_apikey_v1.APIKey{
+			ID:         apiKeyID,
+			Scopes:     []string{"examples:read", "examples:write"},
+			LastUsedAt: &lastUsedAt,
+		})
+		mock_cache.EXPECT().Get(gomock.Any(), cacheKey).Return(string(cached), nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		claims, err := s.ResolveAPIKey(context.Background(), key)
+		assert.NotNil(t, claims)
+		assert.NoError(t, err)
+
+		assert.Equal(t, apiKeyID, claims.Subject)
+		assert.True(t, claims.HasScope("examples:write"))
+	})
+	t.Run("ok - resolve api key from database and record its use", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		apiKeyID := constants.GenerateDataPrefixWithULID(constants.APIKey)
+
+		mock_cache.EXPECT().Get(gomock.Any(), cacheKey).Return("", errors.NewNotFoundError("error"))
+		mock_database.EXPECT().GetAPIKeyByHash(gomock.Any(), hash).Return(&entities_apikey_v1.APIKey{
+			ID:       apiKeyID,
+			TenantID: "acme",
+			Scopes:   []string{"examples:read"},
+		}, nil)
+		mock_database.EXPECT().TouchAPIKey(gomock.Any(), apiKeyID, gomock.Any()).Return(nil)
+		mock_cache.EXPECT().SetEx(gomock.Any(), cacheKey, gomock.Any(), apiKeyCacheDuration).Return(nil).Times(2)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		claims, err := s.ResolveAPIKey(context.Background(), key)
+		assert.NotNil(t, claims)
+		assert.NoError(t, err)
+
+		assert.Equal(t, apiKeyID, claims.Subject)
+		assert.Equal(t, "acme", claims.TenantID)
+		assert.Equal(t, "examples:read", claims.Scope)
+	})
+	t.Run("nok - resolve api key revoked while in use", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		apiKeyID := constants.GenerateDataPrefixWithULID(constants.APIKey)
+
+		cached, _ := json.Marshal(&entities_apikey_v1.APIKey{ID: apiKeyID, Scopes: []string{"examples:read"}})
+		mock_cache.EXPECT().Get(gomock.Any(), cacheKey).Return(string(cached), nil)
+		mock_database.EXPECT().TouchAPIKey(gomock.Any(), apiKeyID, gomock.Any()).Return(errors.NewNotFoundError("error"))
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		claims, err := s.ResolveAPIKey(context.Background(), key)
+		assert.Nil(t, claims)
+		assert.True(t, errors.IsUnauthorizedError(err))
+	})
+	t.Run("nok - resolve unknown api key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		mock_cache.EXPECT().Get(gomock.Any(), cacheKey).Return("", errors.NewNotFoundError("error"))
+		mock_database.EXPECT().GetAPIKeyByHash(gomock.Any(), hash).Return(nil, errors.NewNotFoundError("error"))
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		claims, err := s.ResolveAPIKey(context.Background(), key)
+		assert.Nil(t, claims)
+		assert.True(t, errors.IsUnauthorizedError(err))
+	})
+	t.Run("nok - resolve revoked api key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		revokedAt := time.Now()
+
+		cached, _ := json.Marshal(&entities_apikey_v1.APIKey{ID: "akey_revoked", RevokedAt: &revokedAt})
+		mock_cache.EXPECT().Get(gomock.Any(), cacheKey).Return(string(cached), nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		claims, err := s.ResolveAPIKey(context.Background(), key)
+		assert.Nil(t, claims)
+		assert.True(t, errors.IsUnauthorizedError(err))
+	})
+	t.Run("nok - resolve expired api key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		expiresAt := time.Now().Add(-time.Minute)
+
+		cached, _ := json.Marshal(&entities_apikey_v1.APIKey{ID: "akey_expired", ExpiresAt: &expiresAt})
+		mock_cache.EXPECT().Get(gomock.Any(), cacheKey).Return(string(cached), nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		claims, err := s.ResolveAPIKey(context.Background(), key)
+		assert.Nil(t, claims)
+		assert.True(t, errors.IsExpiredResourceError(err))
+	})
+}
@@ -0,0 +1,111 @@
+package service_v1
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/rs/zerolog/log"
+	entities_audit_v1 "github.com/teyz/go-svc-template/internal/entities/audit/v1"
+	pkg_audit "github.com/teyz/go-svc-template/pkg/audit"
+)
+
+const (
+	auditDefaultLimit = 100
+	auditMaxLimit     = 1000
+)
+
+// recordAudit appends a mutation to the audit log, before or after being nil on
+// creation and purge. It must be called in the transaction of the mutation, so
+// that the mutation is rolled back when it cannot be recorded.
+func (s *service) recordAudit(ctx context.Context, action string, resourceType string, resourceID string, before interface{}, after interface{}) error {
+	entry := newAuditEntry(ctx, action, resourceType, resourceID, before, after)
+
+	if err := s.store.CreateAuditEntry(ctx, entry); err != nil {
+		log.Error().Err(err).
+			Str("action", action).
+			Str("resource_type", resourceType).
+			Str("resource_id", resourceID).
+			Str("actor", entry.Actor).
+			Msg("service.v1.service.recordAudit: unable to record audit entry")
+		return err
+	}
+
+	return nil
+}
+
+// auditChange is a resource before and after a mutation, before being nil on
+// creation.
+type auditChange struct {
+	before interface{}
+	after  interface{}
+}
+
+// recordAudits appends the mutations of a batch to the audit log at once, changes
+// being indexed by resource ID. Like recordAudit, it must be called in the
+// transaction of the mutations.
+func (s *service) recordAudits(ctx context.Context, action string, resourceType string, changes map[string]auditChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	entries := make([]*entities_audit_v1.Entry, 0, len(changes))
+	for resourceID, change := range changes {
+		entries = append(entries, newAuditEntry(ctx, action, resourceType, resourceID, change.before, change.after))
+	}
+
+	if err := s.store.CreateAuditEntries(ctx, entries); err != nil {
+		log.Error().Err(err).
+			Str("action", action).
+			Str("resource_type", resourceType).
+			Int("count", len(entries)).
+			Msg("service.v1.service.recordAudits: unable to record audit entries")
+		return err
+	}
+
+	return nil
+}
+
+func newAuditEntry(ctx context.Context, action string, resourceType string, resourceID string, before interface{}, after interface{}) *entities_audit_v1.Entry {
+	metadata := pkg_audit.MetadataFromContext(ctx)
+
+	return &entities_audit_v1.Entry{
+		Actor:        metadata.Actor,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Before:       marshalAuditState(before),
+		After:        marshalAuditState(after),
+		RequestID:    metadata.RequestID,
+		IP:           metadata.IP,
+	}
+}
+
+func marshalAuditState(state interface{}) json.RawMessage {
+	if state == nil {
+		return nil
+	}
+
+	bytes, err := json.Marshal(state)
+	if err != nil {
+		log.Error().Err(err).
+			Msg("service.v1.service.marshalAuditState: unable to marshal audit state")
+		return nil
+	}
+
+	return bytes
+}
+
+func (s *service) FetchAuditEntries(ctx context.Context, filter entities_audit_v1.Filter) ([]*entities_audit_v1.Entry, error) {
+	if err := s.policy(ctx, ScopeAuditRead, nil); err != nil {
+		return nil, err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = auditDefaultLimit
+	}
+	if filter.Limit > auditMaxLimit {
+		filter.Limit = auditMaxLimit
+	}
+
+	return s.store.FetchAuditEntries(ctx, filter)
+}
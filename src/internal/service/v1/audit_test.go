@@ -0,0 +1,171 @@
+package service_v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	database_mocks "github.com/teyz/go-svc-template/internal/database/mocks"
+	entities_audit_v1 "github.com/teyz/go-svc-template/internal/entities/audit/v1"
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	pkg_audit "github.com/teyz/go-svc-template/pkg/audit"
+	cache_mocks "github.com/teyz/go-svc-template/pkg/cache/mocks"
+	"github.com/teyz/go-svc-template/pkg/constants"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_RecordAudit(t *testing.T) {
+	t.Run("ok - record example creation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
+
+		mock_database.EXPECT().CreateExample(gomock.Any(), "hello world !", "english").Return(&entities_example_v1.Example{
+			ID:          exampleID,
+			Description: "hello world !",
+		}, nil)
+		mock_database.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entry *entities_audit_v1.Entry) error {
+			assert.Equal(t, "user-1", entry.Actor)
+			assert.Equal(t, entities_audit_v1.ActionCreate, entry.Action)
+			assert.Equal(t, entities_audit_v1.ResourceTypeExample, entry.ResourceType)
+			assert.Equal(t, exampleID, entry.ResourceID)
+			assert.Nil(t, entry.Before)
+			assert.Contains(t, string(entry.After), `"description":"hello world !"`)
+			assert.Equal(t, "request-1", entry.RequestID)
+			assert.Equal(t, "10.0.0.1", entry.IP)
+			return nil
+		})
+		mock_cache.EXPECT().InvalidateTags(gomock.Any(), gomock.Any()).Return(nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		ctx := pkg_audit.WithMetadata(context.Background(), pkg_audit.Metadata{
+			Actor:     "user-1",
+			RequestID: "request-1",
+			IP:        "10.0.0.1",
+		})
+
+		example, err := s.CreateExample(ctx, "hello world !")
+		assert.NotNil(t, example)
+		assert.NoError(t, err)
+	})
+	t.Run("nok - roll back example creation when audit fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		mock_database.EXPECT().CreateExample(gomock.Any(), "hello world !", "english").Return(&entities_example_v1.Example{ID: "exmp_1"}, nil)
+		mock_database.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entry *entities_audit_v1.Entry) error {
+			assert.Equal(t, pkg_audit.ActorAnonymous, entry.Actor)
+			return errors.NewInternalServerError("error")
+		})
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		example, err := s.CreateExample(context.Background(), "hello world !")
+		assert.Nil(t, example)
+		assert.Error(t, err)
+	})
+	t.Run("ok - record example deletion with its previous state", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
+		deleted := time.Now()
+
+		mock_database.EXPECT().DeleteExample(gomock.Any(), exampleID).Return(&entities_example_v1.Change{
+			Before: &entities_example_v1.Example{ID: exampleID, Description: "hello world !"},
+			After:  &entities_example_v1.Example{ID: exampleID, Description: "hello world !", DeletedAt: &deleted},
+		}, nil)
+		mock_database.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entry *entities_audit_v1.Entry) error {
+			assert.Equal(t, entities_audit_v1.ActionDelete, entry.Action)
+			assert.NotContains(t, string(entry.Before), `"deleted_at"`)
+			assert.Contains(t, string(entry.After), `"deleted_at"`)
+			return nil
+		})
+		mock_cache.EXPECT().Del(gomock.Any(), gomock.Any()).Return(nil)
+		mock_cache.EXPECT().InvalidateTags(gomock.Any(), gomock.Any()).Return(nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		assert.NoError(t, s.DeleteExample(context.Background(), exampleID))
+	})
+	t.Run("ok - record batch updates with their previous state", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
+		example := &entities_example_v1.Example{ID: exampleID, Description: "updated"}
+
+		mock_database.EXPECT().UpdateExamples(gomock.Any(), gomock.Any(), true).Return([]*entities_example_v1.Change{
+			{Before: &entities_example_v1.Example{ID: exampleID, Description: "original"}, After: example},
+		}, []string{}, nil)
+		mock_database.EXPECT().CreateAuditEntries(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, entries []*entities_audit_v1.Entry) error {
+			assert.Len(t, entries, 1)
+			assert.Contains(t, string(entries[0].Before), `"description":"original"`)
+			assert.Contains(t, string(entries[0].After), `"description":"updated"`)
+			return nil
+		})
+		mock_cache.EXPECT().DelAll(gomock.Any(), gomock.Any()).Return(nil)
+		mock_cache.EXPECT().InvalidateTags(gomock.Any(), gomock.Any()).Return(nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		result, err := s.UpdateExamples(context.Background(), []*entities_example_v1.Example{example}, entities_example_v1.BatchModeAtomic)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Succeeded)
+	})
+}
+
+func Test_FetchAuditEntries(t *testing.T) {
+	t.Run("ok - fetch audit entries with bounded limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		since := time.Now().Add(-time.Hour)
+
+		mock_database.EXPECT().FetchAuditEntries(gomock.Any(), entities_audit_v1.Filter{
+			Actor: "user-1",
+			Since: since,
+			Limit: auditMaxLimit,
+		}).Return([]*entities_audit_v1.Entry{{ID: "audt_1"}}, nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		entries, err := s.FetchAuditEntries(context.Background(), entities_audit_v1.Filter{
+			Actor: "user-1",
+			Since: since,
+			Limit: 5000,
+		})
+		assert.Len(t, entries, 1)
+		assert.NoError(t, err)
+	})
+}
+
+// expectTx makes store run the transactions of the service without a database.
+func expectTx(store *database_mocks.MockDatabase) {
+	store.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+}
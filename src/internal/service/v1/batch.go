@@ -0,0 +1,349 @@
+package service_v1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	entities_audit_v1 "github.com/teyz/go-svc-template/internal/entities/audit/v1"
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	"github.com/teyz/go-svc-template/pkg/constants"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
+)
+
+// ExampleBatchMaxItems is the maximum number of items of a batch.
+const ExampleBatchMaxItems = 1000
+
+// errors reported on the items of a batch
+const (
+	batchErrorDescriptionRequired = "description is required"
+	batchErrorInvalidID           = "invalid id"
+	batchErrorDuplicateID         = "duplicate id"
+	batchErrorNotFound            = "example not found"
+	batchErrorAborted             = "not applied: another item of the batch failed"
+)
+
+// batchResult tracks the outcome of every item of a batch.
+type batchResult struct {
+	*entities_example_v1.BatchResult
+	failed map[int]bool
+}
+
+func newBatchResult(size int) *batchResult {
+	result := &batchResult{
+		BatchResult: &entities_example_v1.BatchResult{
+			Results: make([]*entities_example_v1.BatchItemResult, 0, size),
+		},
+		failed: make(map[int]bool),
+	}
+	for i := 0; i < size; i++ {
+		result.Results = append(result.Results, &entities_example_v1.BatchItemResult{Index: i})
+	}
+
+	return result
+}
+
+func (r *batchResult) succeed(index int, id string) {
+	r.Results[index].ID = id
+	r.Succeeded++
+}
+
+func (r *batchResult) fail(index int, id string, message string) {
+	r.Results[index].ID = id
+	r.Results[index].Error = message
+	r.failed[index] = true
+	r.Failed++
+}
+
+// abort fails the items not failed yet, when an atomic batch can not be fully applied.
+func (r *batchResult) abort() *entities_example_v1.BatchResult {
+	for index, item := range r.Results {
+		if !r.failed[index] {
+			r.fail(index, item.ID, batchErrorAborted)
+		}
+	}
+
+	return r.BatchResult
+}
+
+func validateBatch(method string, size int, mode string) error {
+	if mode != entities_example_v1.BatchModeAtomic && mode != entities_example_v1.BatchModeBestEffort {
+		return errors.NewBadRequestError(fmt.Sprintf("service.v1.service.%s: unknown batch mode %q", method, mode))
+	}
+
+	if size == 0 || size > ExampleBatchMaxItems {
+		return errors.NewBadRequestError(fmt.Sprintf("service.v1.service.%s: a batch holds from 1 to %d items, got %d", method, ExampleBatchMaxItems, size))
+	}
+
+	return nil
+}
+
+// validateBatchIDs fails the items whose ID is invalid or repeated and returns
+// the indexes of the others.
+func validateBatchIDs(result *batchResult, ids []string) []int {
+	valid := make([]int, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+
+	for index, id := range ids {
+		switch {
+		case !constants.Example.IsValid(id):
+			result.fail(index, id, batchErrorInvalidID)
+		case seen[id]:
+			result.fail(index, id, batchErrorDuplicateID)
+		default:
+			valid = append(valid, index)
+		}
+		seen[id] = true
+	}
+
+	return valid
+}
+
+// CreateExamples creates examples from their descriptions. In atomic mode no
+// example is created unless all of them are valid.
+func (s *service) CreateExamples(ctx context.Context, descriptions []string, mode string) (*entities_example_v1.BatchResult, error) {
+	if err := s.policy(ctx, ScopeExamplesWrite, nil); err != nil {
+		return nil, err
+	}
+
+	if err := validateBatch("CreateExamples", len(descriptions), mode); err != nil {
+		return nil, err
+	}
+
+	result := newBatchResult(len(descriptions))
+
+	valid := make([]int, 0, len(descriptions))
+	for index, description := range descriptions {
+		if description == "" {
+			result.fail(index, "", batchErrorDescriptionRequired)
+			continue
+		}
+		valid = append(valid, index)
+	}
+
+	if result.Failed > 0 && mode == entities_example_v1.BatchModeAtomic {
+		return result.abort(), nil
+	}
+	if len(valid) == 0 {
+		return result.BatchResult, nil
+	}
+
+	validDescriptions := make([]string, 0, len(valid))
+	for _, index := range valid {
+		validDescriptions = append(validDescriptions, descriptions[index])
+	}
+
+	var examples []*entities_example_v1.Example
+	err := s.store.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		examples, err = s.store.CreateExamples(ctx, validDescriptions, s.searchLanguage())
+		if err != nil {
+			return err
+		}
+
+		created := make(map[string]auditChange, len(examples))
+		for _, example := range examples {
+			created[example.ID] = auditChange{after: example}
+		}
+
+		return s.recordAudits(ctx, entities_audit_v1.ActionCreate, entities_audit_v1.ResourceTypeExample, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, example := range examples {
+		result.succeed(valid[i], example.ID)
+	}
+
+	s.invalidateExamples(ctx, nil)
+
+	return result.BatchResult, nil
+}
+
+// UpdateExamples updates the description of examples. In atomic mode no
+// example is updated unless all of them are valid and exist.
+func (s *service) UpdateExamples(ctx context.Context, examples []*entities_example_v1.Example, mode string) (*entities_example_v1.BatchResult, error) {
+	if err := validateBatch("UpdateExamples", len(examples), mode); err != nil {
+		return nil, err
+	}
+
+	result := newBatchResult(len(examples))
+
+	ids := make([]string, 0, len(examples))
+	for _, example := range examples {
+		ids = append(ids, example.ID)
+	}
+
+	valid := make([]int, 0, len(examples))
+	for _, index := range validateBatchIDs(result, ids) {
+		if examples[index].Description == "" {
+			result.fail(index, examples[index].ID, batchErrorDescriptionRequired)
+			continue
+		}
+		valid = append(valid, index)
+	}
+
+	if result.Failed > 0 && mode == entities_example_v1.BatchModeAtomic {
+		return result.abort(), nil
+	}
+	if len(valid) == 0 {
+		return result.BatchResult, nil
+	}
+
+	validExamples := make([]*entities_example_v1.Example, 0, len(valid))
+	for _, index := range valid {
+		validExamples = append(validExamples, examples[index])
+	}
+
+	atomic := mode == entities_example_v1.BatchModeAtomic
+
+	var updated []*entities_example_v1.Change
+	var missing []string
+	err := s.store.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		updated, missing, err = s.store.UpdateExamples(ctx, validExamples, atomic)
+		if err != nil {
+			return err
+		}
+
+		if err := s.authorizeBatchWrite(ctx, updated); err != nil {
+			return err
+		}
+
+		return s.recordAudits(ctx, entities_audit_v1.ActionUpdate, entities_audit_v1.ResourceTypeExample, batchAuditChanges(updated))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.applyBatchWrite(ctx, result, ids, valid, updated, missing, atomic)
+
+	return result.BatchResult, nil
+}
+
+// DeleteExamples soft-deletes examples. In atomic mode no example is deleted
+// unless all of them are valid and exist.
+func (s *service) DeleteExamples(ctx context.Context, ids []string, mode string) (*entities_example_v1.BatchResult, error) {
+	if err := validateBatch("DeleteExamples", len(ids), mode); err != nil {
+		return nil, err
+	}
+
+	result := newBatchResult(len(ids))
+
+	valid := validateBatchIDs(result, ids)
+
+	if result.Failed > 0 && mode == entities_example_v1.BatchModeAtomic {
+		return result.abort(), nil
+	}
+	if len(valid) == 0 {
+		return result.BatchResult, nil
+	}
+
+	validIDs := make([]string, 0, len(valid))
+	for _, index := range valid {
+		validIDs = append(validIDs, ids[index])
+	}
+
+	atomic := mode == entities_example_v1.BatchModeAtomic
+
+	var deleted []*entities_example_v1.Change
+	var missing []string
+	err := s.store.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		deleted, missing, err = s.store.DeleteExamples(ctx, validIDs, atomic)
+		if err != nil {
+			return err
+		}
+
+		if err := s.authorizeBatchWrite(ctx, deleted); err != nil {
+			return err
+		}
+
+		return s.recordAudits(ctx, entities_audit_v1.ActionDelete, entities_audit_v1.ResourceTypeExample, batchAuditChanges(deleted))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.applyBatchWrite(ctx, result, ids, valid, deleted, missing, atomic)
+
+	return result.BatchResult, nil
+}
+
+// authorizeBatchWrite checks the policy on every example written by a batch, as
+// it was before, so that a denied example rolls back the whole batch.
+func (s *service) authorizeBatchWrite(ctx context.Context, written []*entities_example_v1.Change) error {
+	for _, change := range written {
+		if err := s.policy(ctx, ScopeExamplesWrite, change.Before); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// batchAuditChanges indexes the changes of a batch write by example ID.
+func batchAuditChanges(written []*entities_example_v1.Change) map[string]auditChange {
+	changes := make(map[string]auditChange, len(written))
+	for _, change := range written {
+		changes[change.After.ID] = auditChange{before: change.Before, after: change.After}
+	}
+
+	return changes
+}
+
+// applyBatchWrite reports the outcome of a batch write of existing examples,
+// then invalidates the cache once.
+func (s *service) applyBatchWrite(ctx context.Context, result *batchResult, ids []string, valid []int, written []*entities_example_v1.Change, missing []string, atomic bool) {
+	indexes := make(map[string]int, len(valid))
+	for _, index := range valid {
+		indexes[ids[index]] = index
+	}
+
+	for _, id := range missing {
+		result.fail(indexes[id], id, batchErrorNotFound)
+	}
+
+	// an atomic write with missing examples is rolled back
+	if atomic && len(missing) > 0 {
+		result.abort()
+		return
+	}
+
+	writtenIDs := make([]string, 0, len(written))
+	for _, change := range written {
+		id := change.After.ID
+		result.succeed(indexes[id], id)
+		writtenIDs = append(writtenIDs, id)
+	}
+
+	s.invalidateExamples(ctx, writtenIDs)
+}
+
+// invalidateExamples removes examples from the cache along with the lists of
+// their tenant, with a single call for each.
+func (s *service) invalidateExamples(ctx context.Context, ids []string) {
+	tenant := pkg_tenant.FromContext(ctx)
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, generateExampleCacheKeyWithID(tenant, id))
+	}
+
+	s.invalidateTwice(ctx, func(ctx context.Context) {
+		if len(keys) > 0 {
+			if err := s.cache.DelAll(ctx, keys...); err != nil {
+				log.Error().Err(err).
+					Int("count", len(keys)).
+					Msg("service.v1.service.invalidateExamples: unable to remove examples from cache")
+			}
+		}
+
+		if err := s.cache.InvalidateTags(ctx, generateExamplesCacheTag(tenant)); err != nil {
+			log.Error().Err(err).
+				Msg("service.v1.service.invalidateExamples: unable to invalidate examples cache")
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package service_v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	database_mocks "github.com/teyz/go-svc-template/internal/database/mocks"
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	cache_mocks "github.com/teyz/go-svc-template/pkg/cache/mocks"
+	"github.com/teyz/go-svc-template/pkg/constants"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_CreateExamples(t *testing.T) {
+	t.Run("ok - create examples in best effort", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		mock_database.EXPECT().CreateExamples(gomock.Any(), []string{"first", "third"}, "english").Return([]*entities_example_v1.Example{
+			{ID: "exmp_1", Description: "first"},
+			{ID: "exmp_3", Description: "third"},
+		}, nil)
+		mock_database.EXPECT().CreateAuditEntries(gomock.Any(), gomock.Len(2)).Return(nil)
+		mock_cache.EXPECT().InvalidateTags(gomock.Any(), "go-svc-template:tenant:default:tag:examples").Return(nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		result, err := s.CreateExamples(context.Background(), []string{"first", "", "third"}, entities_example_v1.BatchModeBestEffort)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 2, result.Succeeded)
+		assert.Equal(t, 1, result.Failed)
+		assert.Equal(t, &entities_example_v1.BatchItemResult{Index: 0, ID: "exmp_1"}, result.Results[0])
+		assert.Equal(t, &entities_example_v1.BatchItemResult{Index: 1, Error: batchErrorDescriptionRequired}, result.Results[1])
+		assert.Equal(t, &entities_example_v1.BatchItemResult{Index: 2, ID: "exmp_3"}, result.Results[2])
+	})
+	t.Run("ok - create nothing in atomic mode with an invalid item", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		result, err := s.CreateExamples(context.Background(), []string{"first", ""}, entities_example_v1.BatchModeAtomic)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 0, result.Succeeded)
+		assert.Equal(t, 2, result.Failed)
+		assert.Equal(t, batchErrorAborted, result.Results[0].Error)
+		assert.Equal(t, batchErrorDescriptionRequired, result.Results[1].Error)
+	})
+	t.Run("nok - create examples with an unknown mode", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		result, err := s.CreateExamples(context.Background(), []string{"first"}, "sometimes")
+		assert.Nil(t, result)
+		assert.True(t, errors.IsBadRequestError(err))
+	})
+	t.Run("nok - create too many examples", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		result, err := s.CreateExamples(context.Background(), make([]string, ExampleBatchMaxItems+1), entities_example_v1.BatchModeAtomic)
+		assert.Nil(t, result)
+		assert.True(t, errors.IsBadRequestError(err))
+	})
+}
+
+func Test_UpdateExamples(t *testing.T) {
+	t.Run("ok - update examples in best effort", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		first := constants.GenerateDataPrefixWithULID(constants.Example)
+		second := constants.GenerateDataPrefixWithULID(constants.Example)
+
+		examples := []*entities_example_v1.Example{
+			{ID: first, Description: "first"},
+			{ID: "invalid", Description: "invalid"},
+			{ID: second, Description: "second"},
+			{ID: first, Description: "again"},
+		}
+
+		mock_database.EXPECT().UpdateExamples(gomock.Any(), []*entities_example_v1.Example{examples[0], examples[2]}, false).
+			Return([]*entities_example_v1.Change{{Before: &entities_example_v1.Example{ID: first}, After: examples[0]}}, []string{second}, nil)
+		mock_database.EXPECT().CreateAuditEntries(gomock.Any(), gomock.Len(1)).Return(nil)
+		mock_cache.EXPECT().DelAll(gomock.Any(), "go-svc-template:tenant:default:example:id:"+first).Return(nil)
+		mock_cache.EXPECT().InvalidateTags(gomock.Any(), "go-svc-template:tenant:default:tag:examples").Return(nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		result, err := s.UpdateExamples(context.Background(), examples, entities_example_v1.BatchModeBestEffort)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 1, result.Succeeded)
+		assert.Equal(t, 3, result.Failed)
+		assert.Empty(t, result.Results[0].Error)
+		assert.Equal(t, batchErrorInvalidID, result.Results[1].Error)
+		assert.Equal(t, batchErrorNotFound, result.Results[2].Error)
+		assert.Equal(t, batchErrorDuplicateID, result.Results[3].Error)
+	})
+	t.Run("ok - update nothing in atomic mode with a missing example", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		first := constants.GenerateDataPrefixWithULID(constants.Example)
+		second := constants.GenerateDataPrefixWithULID(constants.Example)
+
+		mock_database.EXPECT().UpdateExamples(gomock.Any(), gomock.Any(), true).Return(nil, []string{second}, nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		result, err := s.UpdateExamples(context.Background(), []*entities_example_v1.Example{
+			{ID: first, Description: "first"},
+			{ID: second, Description: "second"},
+		}, entities_example_v1.BatchModeAtomic)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 0, result.Succeeded)
+		assert.Equal(t, batchErrorAborted, result.Results[0].Error)
+		assert.Equal(t, batchErrorNotFound, result.Results[1].Error)
+	})
+}
+
+func Test_DeleteExamples(t *testing.T) {
+	t.Run("ok - delete examples", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		first := constants.GenerateDataPrefixWithULID(constants.Example)
+		second := constants.GenerateDataPrefixWithULID(constants.Example)
+
+		mock_database.EXPECT().DeleteExamples(gomock.Any(), []string{first, second}, true).Return([]*entities_example_v1.Change{
+			{Before: &entities_example_v1.Example{ID: first}, After: &entities_example_v1.Example{ID: first}},
+			{Before: &entities_example_v1.Example{ID: second}, After: &entities_example_v1.Example{ID: second}},
+		}, []string{}, nil)
+		mock_database.EXPECT().CreateAuditEntries(gomock.Any(), gomock.Len(2)).Return(nil)
+		mock_cache.EXPECT().DelAll(gomock.Any(), "go-svc-template:tenant:default:example:id:"+first, "go-svc-template:tenant:default:example:id:"+second).Return(nil)
+		mock_cache.EXPECT().InvalidateTags(gomock.Any(), "go-svc-template:tenant:default:tag:examples").Return(nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		result, err := s.DeleteExamples(context.Background(), []string{first, second}, entities_example_v1.BatchModeAtomic)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 2, result.Succeeded)
+		assert.Equal(t, 0, result.Failed)
+	})
+	t.Run("nok - delete examples when one is denied by policy", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		first := constants.GenerateDataPrefixWithULID(constants.Example)
+		second := constants.GenerateDataPrefixWithULID(constants.Example)
+
+		mock_database.EXPECT().DeleteExamples(gomock.Any(), []string{first, second}, false).Return([]*entities_example_v1.Change{
+			{Before: &entities_example_v1.Example{ID: first}, After: &entities_example_v1.Example{ID: first}},
+			{Before: &entities_example_v1.Example{ID: second}, After: &entities_example_v1.Example{ID: second}},
+		}, []string{}, nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		s.SetPolicy(func(ctx context.Context, action string, resource interface{}) error {
+			if example, ok := resource.(*entities_example_v1.Example); ok && example.ID == second {
+				return errors.NewForbiddenError("denied")
+			}
+			return nil
+		})
+
+		result, err := s.DeleteExamples(context.Background(), []string{first, second}, entities_example_v1.BatchModeBestEffort)
+		assert.Nil(t, result)
+		assert.True(t, errors.IsForbiddenError(err))
+	})
+	t.Run("nok - delete examples", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		mock_database.EXPECT().DeleteExamples(gomock.Any(), gomock.Any(), false).Return(nil, nil, errors.NewInternalServerError("error"))
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		result, err := s.DeleteExamples(context.Background(), []string{constants.GenerateDataPrefixWithULID(constants.Example)}, entities_example_v1.BatchModeBestEffort)
+		assert.Nil(t, result)
+		assert.Error(t, err)
+	})
+}
@@ -3,24 +3,54 @@ package service_v1
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"time"
 
 	"github.com/rs/zerolog/log"
+	entities_audit_v1 "github.com/teyz/go-svc-template/internal/entities/audit/v1"
 	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
 )
 
 func (s *service) CreateExample(ctx context.Context, description string) (*entities_example_v1.Example, error) {
-	example, err := s.store.CreateExample(ctx, description)
+	if err := s.policy(ctx, ScopeExamplesWrite, nil); err != nil {
+		return nil, err
+	}
+
+	var example *entities_example_v1.Example
+	err := s.store.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		example, err = s.store.CreateExample(ctx, description, s.searchLanguage())
+		if err != nil {
+			return err
+		}
+
+		return s.recordAudit(ctx, entities_audit_v1.ActionCreate, entities_audit_v1.ResourceTypeExample, example.ID, nil, example)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	s.cache.Del(ctx, generateExamplesCacheKey())
+	s.invalidateExamples(ctx, nil)
 
 	return example, nil
 }
 
-func (s *service) FetchExamples(ctx context.Context) ([]*entities_example_v1.Example, error) {
-	key := generateExamplesCacheKey()
+// FetchExamples returns the examples of the tenant with only the given fields
+// set, or all of them when fields is empty.
+func (s *service) FetchExamples(ctx context.Context, includeDeleted bool, fields []string) ([]*entities_example_v1.Example, error) {
+	if err := s.policy(ctx, ScopeExamplesRead, nil); err != nil {
+		return nil, err
+	}
+
+	fields, err := normalizeFields("FetchExamples", fields)
+	if err != nil {
+		return nil, err
+	}
+
+	tenant := pkg_tenant.FromContext(ctx)
+	key := withFieldsCacheKey(generateExamplesCacheKey(tenant, includeDeleted), fields)
 
 	cacheExamples, err := s.cache.Get(ctx, key)
 	if err == nil {
@@ -34,7 +64,7 @@ func (s *service) FetchExamples(ctx context.Context) ([]*entities_example_v1.Exa
 		}
 	}
 
-	examples, err := s.store.FetchExamples(ctx)
+	examples, err := s.store.FetchExamples(ctx, includeDeleted, fields)
 	if err != nil {
 		return nil, err
 	}
@@ -44,14 +74,35 @@ func (s *service) FetchExamples(ctx context.Context) ([]*entities_example_v1.Exa
 		log.Error().Err(err).
 			Msg("service.v1.service.FetchExamples: unable to marshal examples")
 	} else {
-		s.cache.SetEx(ctx, key, bytes, exampleCacheDuration)
+		s.cache.SetExWithTags(ctx, key, bytes, s.cacheTTL(), generateExamplesCacheTag(tenant))
 	}
 
 	return examples, nil
 }
 
-func (s *service) GetExampleByID(ctx context.Context, id string) (*entities_example_v1.Example, error) {
-	key := generateExampleCacheKeyWithID(id)
+// GetExampleByID returns an example with only the given fields set, or all of
+// them when fields is empty.
+func (s *service) GetExampleByID(ctx context.Context, id string, fields []string) (*entities_example_v1.Example, error) {
+	fields, err := normalizeFields("GetExampleByID", fields)
+	if err != nil {
+		return nil, err
+	}
+
+	example, err := s.getExampleByID(ctx, id, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy(ctx, ScopeExamplesRead, example); err != nil {
+		return nil, err
+	}
+
+	return example, nil
+}
+
+func (s *service) getExampleByID(ctx context.Context, id string, fields []string) (*entities_example_v1.Example, error) {
+	tenant := pkg_tenant.FromContext(ctx)
+	key := withFieldsCacheKey(generateExampleCacheKeyWithID(tenant, id), fields)
 
 	cacheExample, err := s.cache.Get(ctx, key)
 	if err == nil {
@@ -65,18 +116,178 @@ func (s *service) GetExampleByID(ctx context.Context, id string) (*entities_exam
 		}
 	}
 
-	example, err := s.store.GetExampleByID(ctx, id)
+	example, err := s.store.GetExampleByID(ctx, id, fields)
 	if err != nil {
 		return nil, err
 	}
 
 	bytes, err := json.Marshal(example)
-	if err != nil {
+	switch {
+	case err != nil:
 		log.Error().Err(err).
 			Msg("service.v1.service.GetExampleByID: unable to marshal example")
-	} else {
-		s.cache.SetEx(ctx, key, bytes, exampleCacheDuration)
+	case len(fields) > 0:
+		// partial examples are only removed from the cache along with the lists
+		s.cache.SetExWithTags(ctx, key, bytes, s.cacheTTL(), generateExamplesCacheTag(tenant))
+	default:
+		s.cache.SetEx(ctx, key, bytes, s.cacheTTL())
 	}
 
 	return example, nil
 }
+
+func (s *service) DeleteExample(ctx context.Context, id string) error {
+	var change *entities_example_v1.Change
+	err := s.store.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		change, err = s.store.DeleteExample(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// checked before committing, on the example as it was
+		if err := s.policy(ctx, ScopeExamplesWrite, change.Before); err != nil {
+			return err
+		}
+
+		return s.recordAudit(ctx, entities_audit_v1.ActionDelete, entities_audit_v1.ResourceTypeExample, change.After.ID, change.Before, change.After)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateExample(ctx, pkg_tenant.FromContext(ctx), change.After.ID)
+
+	return nil
+}
+
+func (s *service) RestoreExample(ctx context.Context, id string) (*entities_example_v1.Example, error) {
+	var change *entities_example_v1.Change
+	err := s.store.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		change, err = s.store.RestoreExample(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// checked before committing, on the example as it was
+		if err := s.policy(ctx, ScopeExamplesWrite, change.Before); err != nil {
+			return err
+		}
+
+		return s.recordAudit(ctx, entities_audit_v1.ActionRestore, entities_audit_v1.ResourceTypeExample, change.After.ID, change.Before, change.After)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateExample(ctx, pkg_tenant.FromContext(ctx), change.After.ID)
+
+	return change.After, nil
+}
+
+// ExportExamples calls fn with every example selected by filter, oldest first,
+// without caching them.
+func (s *service) ExportExamples(ctx context.Context, filter entities_example_v1.ExportFilter, fn func(*entities_example_v1.Example) error) error {
+	if err := s.policy(ctx, ScopeExamplesRead, nil); err != nil {
+		return err
+	}
+
+	return s.store.ExportExamples(ctx, filter, fn)
+}
+
+// PurgeDeletedExamples hard-deletes up to limit examples of every tenant
+// soft-deleted before deletedBefore and returns how many were purged.
+func (s *service) PurgeDeletedExamples(ctx context.Context, deletedBefore time.Time, limit int) (int, error) {
+	var purged []*entities_example_v1.PurgedExample
+	err := s.store.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		purged, err = s.store.PurgeExamples(ctx, deletedBefore, limit)
+		if err != nil {
+			return err
+		}
+
+		for _, example := range purged {
+			err := s.recordAudit(pkg_tenant.WithTenant(ctx, example.TenantID), entities_audit_v1.ActionPurge, entities_audit_v1.ResourceTypeExample, example.ID, nil, nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, example := range purged {
+		s.invalidateExample(pkg_tenant.WithTenant(ctx, example.TenantID), example.TenantID, example.ID)
+	}
+
+	return len(purged), nil
+}
+
+// normalizeFields rejects unknown fields and sorts the others in the order they
+// are returned, so that a selection always makes the same cache key.
+func normalizeFields(method string, fields []string) ([]string, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	known := make(map[string]bool, len(entities_example_v1.Fields))
+	for _, field := range entities_example_v1.Fields {
+		known[field] = true
+	}
+
+	selected := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if !known[field] {
+			return nil, errors.NewBadRequestError(fmt.Sprintf("service.v1.service.%s: unknown field %q", method, field))
+		}
+		selected[field] = true
+	}
+
+	normalized := make([]string, 0, len(selected))
+	for _, field := range entities_example_v1.Fields {
+		if selected[field] {
+			normalized = append(normalized, field)
+		}
+	}
+
+	return normalized, nil
+}
+
+// invalidateExample removes an example from the cache along with the lists of its tenant.
+func (s *service) invalidateExample(ctx context.Context, tenant string, id string) {
+	s.invalidateTwice(ctx, func(ctx context.Context) {
+		err := s.cache.Del(ctx, generateExampleCacheKeyWithID(tenant, id))
+		if err != nil {
+			log.Error().Err(err).
+				Str("id", id).
+				Msg("service.v1.service.invalidateExample: unable to remove example from cache")
+		}
+
+		err = s.cache.InvalidateTags(ctx, generateExamplesCacheTag(tenant))
+		if err != nil {
+			log.Error().Err(err).
+				Msg("service.v1.service.invalidateExample: unable to invalidate examples cache")
+		}
+	})
+}
+
+// invalidateTwice calls invalidate now and, when reads are served by replicas,
+// once more after their lag: a read served in between by a replica that did not
+// apply the mutation yet puts back in the cache the examples of before it.
+func (s *service) invalidateTwice(ctx context.Context, invalidate func(ctx context.Context)) {
+	invalidate(ctx)
+
+	lag := time.Duration(s.replicaLag.Load())
+	if lag <= 0 {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	time.AfterFunc(lag, func() {
+		invalidate(ctx)
+	})
+}
@@ -13,6 +13,7 @@ import (
 	cache_mocks "github.com/teyz/go-svc-template/pkg/cache/mocks"
 	"github.com/teyz/go-svc-template/pkg/constants"
 	"github.com/teyz/go-svc-template/pkg/errors"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
 	"go.uber.org/mock/gomock"
 )
 
@@ -21,18 +22,20 @@ func Test_CreateExample(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		mock_database := database_mocks.NewMockDatabase(ctrl)
 		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
 
 		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
 		created := time.Now()
 
-		mock_database.EXPECT().CreateExample(gomock.Any(), "hello world !").Return(&entities_example_v1.Example{
+		mock_database.EXPECT().CreateExample(gomock.Any(), "hello world !", "english").Return(&entities_example_v1.Example{
 			ID:          exampleID,
 			Description: "hello world !",
 			CreatedAt:   created,
 			UpdatedAt:   created,
 		}, nil)
 
-		mock_cache.EXPECT().Del(gomock.Any(), generateExamplesCacheKey())
+		mock_database.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Return(nil)
+		mock_cache.EXPECT().InvalidateTags(gomock.Any(), "go-svc-template:tenant:default:tag:examples").Return(nil)
 
 		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
 		assert.NotNil(t, s)
@@ -47,11 +50,41 @@ func Test_CreateExample(t *testing.T) {
 		assert.True(t, example.CreatedAt.Equal(created))
 		assert.True(t, example.UpdatedAt.Equal(created))
 	})
+	t.Run("ok - create example when cache invalidation fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
+		created := time.Now()
+
+		mock_database.EXPECT().CreateExample(gomock.Any(), "hello world !", "english").Return(&entities_example_v1.Example{
+			ID:          exampleID,
+			Description: "hello world !",
+			CreatedAt:   created,
+			UpdatedAt:   created,
+		}, nil)
+
+		mock_database.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Return(nil)
+		mock_cache.EXPECT().InvalidateTags(gomock.Any(), "go-svc-template:tenant:default:tag:examples").Return(errors.NewInternalServerError("error"))
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		example, err := s.CreateExample(context.Background(), "hello world !")
+		assert.NotNil(t, example)
+		assert.NoError(t, err)
+
+		assert.Equal(t, exampleID, example.ID)
+	})
 	t.Run("nok - create example", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		mock_database := database_mocks.NewMockDatabase(ctrl)
 
-		mock_database.EXPECT().CreateExample(gomock.Any(), "hello world !").Return(nil, errors.NewInternalServerError("error"))
+		expectTx(mock_database)
+		mock_database.EXPECT().CreateExample(gomock.Any(), "hello world !", "english").Return(nil, errors.NewInternalServerError("error"))
 
 		mock_cache := cache_mocks.NewMockCache(ctrl)
 
@@ -63,6 +96,24 @@ func Test_CreateExample(t *testing.T) {
 		assert.Nil(t, example)
 		assert.Error(t, err)
 	})
+	t.Run("nok - create example when denied by policy", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		s.SetPolicy(func(ctx context.Context, action string, resource interface{}) error {
+			assert.Equal(t, ScopeExamplesWrite, action)
+			return errors.NewForbiddenError("denied")
+		})
+
+		example, err := s.CreateExample(context.Background(), "hello world !")
+		assert.Nil(t, example)
+		assert.True(t, errors.IsForbiddenError(err))
+	})
 }
 
 func Test_GetExampleByID(t *testing.T) {
@@ -83,13 +134,13 @@ func Test_GetExampleByID(t *testing.T) {
 
 		exampleCachedBytes, _ := json.Marshal(exampleCached)
 
-		mock_cache.EXPECT().Get(gomock.Any(), fmt.Sprintf("go-svc-template:example:id:%v", exampleID)).Return(string(exampleCachedBytes), nil)
+		mock_cache.EXPECT().Get(gomock.Any(), fmt.Sprintf("go-svc-template:tenant:default:example:id:%v", exampleID)).Return(string(exampleCachedBytes), nil)
 
 		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
 		assert.NotNil(t, s)
 		assert.NoError(t, err)
 
-		example, err := s.GetExampleByID(context.Background(), exampleID)
+		example, err := s.GetExampleByID(context.Background(), exampleID, nil)
 		assert.NotNil(t, example)
 		assert.NoError(t, err)
 
@@ -98,6 +149,28 @@ func Test_GetExampleByID(t *testing.T) {
 		assert.True(t, example.CreatedAt.Equal(created))
 		assert.True(t, example.UpdatedAt.Equal(created))
 	})
+	t.Run("ok - get example by id from cache of tenant", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
+
+		exampleCachedBytes, _ := json.Marshal(&entities_example_v1.Example{
+			ID:          exampleID,
+			Description: "hello world !",
+		})
+
+		mock_cache.EXPECT().Get(gomock.Any(), fmt.Sprintf("go-svc-template:tenant:acme:example:id:%v", exampleID)).Return(string(exampleCachedBytes), nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		example, err := s.GetExampleByID(pkg_tenant.WithTenant(context.Background(), "acme"), exampleID, nil)
+		assert.NotNil(t, example)
+		assert.NoError(t, err)
+	})
 	t.Run("ok - get example by id from database", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		mock_database := database_mocks.NewMockDatabase(ctrl)
@@ -106,14 +179,14 @@ func Test_GetExampleByID(t *testing.T) {
 		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
 		created := time.Now()
 
-		mock_database.EXPECT().GetExampleByID(gomock.Any(), exampleID).Return(&entities_example_v1.Example{
+		mock_database.EXPECT().GetExampleByID(gomock.Any(), exampleID, nil).Return(&entities_example_v1.Example{
 			ID:          exampleID,
 			Description: "hello world !",
 			CreatedAt:   created,
 			UpdatedAt:   created,
 		}, nil)
 
-		mock_cache.EXPECT().Get(gomock.Any(), fmt.Sprintf("go-svc-template:example:id:%v", exampleID)).Return("", errors.NewNotFoundError("error"))
+		mock_cache.EXPECT().Get(gomock.Any(), fmt.Sprintf("go-svc-template:tenant:default:example:id:%v", exampleID)).Return("", errors.NewNotFoundError("error"))
 
 		exampleCached := &entities_example_v1.Example{
 			ID:          exampleID,
@@ -124,13 +197,13 @@ func Test_GetExampleByID(t *testing.T) {
 
 		exampleCachedBytes, _ := json.Marshal(exampleCached)
 
-		mock_cache.EXPECT().SetEx(gomock.Any(), fmt.Sprintf("go-svc-template:example:id:%v", exampleID), exampleCachedBytes, time.Hour*24).Return(nil)
+		mock_cache.EXPECT().SetEx(gomock.Any(), fmt.Sprintf("go-svc-template:tenant:default:example:id:%v", exampleID), exampleCachedBytes, time.Hour*24).Return(nil)
 
 		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
 		assert.NotNil(t, s)
 		assert.NoError(t, err)
 
-		example, err := s.GetExampleByID(context.Background(), exampleID)
+		example, err := s.GetExampleByID(context.Background(), exampleID, nil)
 		assert.NotNil(t, example)
 		assert.NoError(t, err)
 
@@ -139,20 +212,57 @@ func Test_GetExampleByID(t *testing.T) {
 		assert.True(t, example.CreatedAt.Equal(created))
 		assert.True(t, example.UpdatedAt.Equal(created))
 	})
+	t.Run("ok - get example by id with selected fields", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
+		key := fmt.Sprintf("go-svc-template:tenant:default:example:id:%v:fields:id,description", exampleID)
+
+		mock_cache.EXPECT().Get(gomock.Any(), key).Return("", errors.NewNotFoundError("error"))
+		mock_database.EXPECT().GetExampleByID(gomock.Any(), exampleID, []string{"id", "description"}).Return(&entities_example_v1.Example{
+			ID:          exampleID,
+			Description: "hello world !",
+		}, nil)
+		mock_cache.EXPECT().SetExWithTags(gomock.Any(), key, gomock.Any(), time.Hour*24, "go-svc-template:tenant:default:tag:examples").Return(nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		example, err := s.GetExampleByID(context.Background(), exampleID, []string{"description", "id", "description"})
+		assert.NoError(t, err)
+		assert.Equal(t, exampleID, example.ID)
+		assert.Equal(t, "hello world !", example.Description)
+	})
+	t.Run("nok - get example by id with an unknown field", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		example, err := s.GetExampleByID(context.Background(), "exmp_1", []string{"id", "tenant_id"})
+		assert.Nil(t, example)
+		assert.True(t, errors.IsBadRequestError(err))
+	})
 	t.Run("nok - get example by id from database", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		mock_database := database_mocks.NewMockDatabase(ctrl)
 		mock_cache := cache_mocks.NewMockCache(ctrl)
 
-		mock_database.EXPECT().GetExampleByID(gomock.Any(), "id").Return(nil, errors.NewNotFoundError("error"))
+		mock_database.EXPECT().GetExampleByID(gomock.Any(), "id", nil).Return(nil, errors.NewNotFoundError("error"))
 
-		mock_cache.EXPECT().Get(gomock.Any(), fmt.Sprintf("go-svc-template:example:id:%v", "id")).Return("", errors.NewNotFoundError("error"))
+		mock_cache.EXPECT().Get(gomock.Any(), fmt.Sprintf("go-svc-template:tenant:default:example:id:%v", "id")).Return("", errors.NewNotFoundError("error"))
 
 		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
 		assert.NotNil(t, s)
 		assert.NoError(t, err)
 
-		example, err := s.GetExampleByID(context.Background(), "id")
+		example, err := s.GetExampleByID(context.Background(), "id", nil)
 		assert.Nil(t, example)
 		assert.Error(t, err)
 	})
@@ -166,9 +276,9 @@ func Test_GetExampleByID(t *testing.T) {
 		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
 		created := time.Now()
 
-		mock_cache.EXPECT().Get(gomock.Any(), fmt.Sprintf("go-svc-template:example:id:%v", exampleID)).Return(fakeData, nil)
+		mock_cache.EXPECT().Get(gomock.Any(), fmt.Sprintf("go-svc-template:tenant:default:example:id:%v", exampleID)).Return(fakeData, nil)
 
-		mock_database.EXPECT().GetExampleByID(gomock.Any(), exampleID).Return(&entities_example_v1.Example{
+		mock_database.EXPECT().GetExampleByID(gomock.Any(), exampleID, nil).Return(&entities_example_v1.Example{
 			ID:          exampleID,
 			Description: "hello world !",
 			CreatedAt:   created,
@@ -184,13 +294,13 @@ func Test_GetExampleByID(t *testing.T) {
 
 		exampleCachedBytes, _ := json.Marshal(exampleCached)
 
-		mock_cache.EXPECT().SetEx(gomock.Any(), fmt.Sprintf("go-svc-template:example:id:%v", exampleID), exampleCachedBytes, time.Hour*24).Return(nil)
+		mock_cache.EXPECT().SetEx(gomock.Any(), fmt.Sprintf("go-svc-template:tenant:default:example:id:%v", exampleID), exampleCachedBytes, time.Hour*24).Return(nil)
 
 		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
 		assert.NotNil(t, s)
 		assert.NoError(t, err)
 
-		example, err := s.GetExampleByID(context.Background(), exampleID)
+		example, err := s.GetExampleByID(context.Background(), exampleID, nil)
 		assert.NotNil(t, example)
 		assert.NoError(t, err)
 
@@ -221,13 +331,13 @@ func Test_GetExamples(t *testing.T) {
 
 		exampleCachedBytes, _ := json.Marshal(examplesCached)
 
-		mock_cache.EXPECT().Get(gomock.Any(), "go-svc-template:examples").Return(string(exampleCachedBytes), nil)
+		mock_cache.EXPECT().Get(gomock.Any(), "go-svc-template:tenant:default:examples").Return(string(exampleCachedBytes), nil)
 
 		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
 		assert.NotNil(t, s)
 		assert.NoError(t, err)
 
-		examples, err := s.FetchExamples(context.Background())
+		examples, err := s.FetchExamples(context.Background(), false, nil)
 		assert.NotNil(t, examples)
 		assert.NoError(t, err)
 
@@ -255,19 +365,19 @@ func Test_GetExamples(t *testing.T) {
 			},
 		}
 
-		mock_database.EXPECT().FetchExamples(gomock.Any()).Return(examplesResults, nil)
+		mock_database.EXPECT().FetchExamples(gomock.Any(), false, nil).Return(examplesResults, nil)
 
-		mock_cache.EXPECT().Get(gomock.Any(), "go-svc-template:examples").Return("", errors.NewNotFoundError("error"))
+		mock_cache.EXPECT().Get(gomock.Any(), "go-svc-template:tenant:default:examples").Return("", errors.NewNotFoundError("error"))
 
 		exampleCachedBytes, _ := json.Marshal(examplesResults)
 
-		mock_cache.EXPECT().SetEx(gomock.Any(), "go-svc-template:examples", exampleCachedBytes, time.Hour*24).Return(nil)
+		mock_cache.EXPECT().SetExWithTags(gomock.Any(), "go-svc-template:tenant:default:examples", exampleCachedBytes, time.Hour*24, "go-svc-template:tenant:default:tag:examples").Return(nil)
 
 		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
 		assert.NotNil(t, s)
 		assert.NoError(t, err)
 
-		examples, err := s.FetchExamples(context.Background())
+		examples, err := s.FetchExamples(context.Background(), false, nil)
 		assert.NotNil(t, examples)
 		assert.NoError(t, err)
 
@@ -283,15 +393,15 @@ func Test_GetExamples(t *testing.T) {
 		mock_database := database_mocks.NewMockDatabase(ctrl)
 		mock_cache := cache_mocks.NewMockCache(ctrl)
 
-		mock_database.EXPECT().FetchExamples(gomock.Any()).Return(nil, errors.NewNotFoundError("error"))
+		mock_database.EXPECT().FetchExamples(gomock.Any(), false, nil).Return(nil, errors.NewNotFoundError("error"))
 
-		mock_cache.EXPECT().Get(gomock.Any(), "go-svc-template:examples").Return("", errors.NewNotFoundError("error"))
+		mock_cache.EXPECT().Get(gomock.Any(), "go-svc-template:tenant:default:examples").Return("", errors.NewNotFoundError("error"))
 
 		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
 		assert.NotNil(t, s)
 		assert.NoError(t, err)
 
-		example, err := s.FetchExamples(context.Background())
+		example, err := s.FetchExamples(context.Background(), false, nil)
 		assert.Nil(t, example)
 		assert.Error(t, err)
 	})
@@ -305,7 +415,7 @@ func Test_GetExamples(t *testing.T) {
 		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
 		created := time.Now()
 
-		mock_cache.EXPECT().Get(gomock.Any(), "go-svc-template:examples").Return(fakeData, nil)
+		mock_cache.EXPECT().Get(gomock.Any(), "go-svc-template:tenant:default:examples").Return(fakeData, nil)
 
 		examplesResults := []*entities_example_v1.Example{
 			{
@@ -316,17 +426,17 @@ func Test_GetExamples(t *testing.T) {
 			},
 		}
 
-		mock_database.EXPECT().FetchExamples(gomock.Any()).Return(examplesResults, nil)
+		mock_database.EXPECT().FetchExamples(gomock.Any(), false, nil).Return(examplesResults, nil)
 
 		exampleCachedBytes, _ := json.Marshal(examplesResults)
 
-		mock_cache.EXPECT().SetEx(gomock.Any(), "go-svc-template:examples", exampleCachedBytes, time.Hour*24).Return(nil)
+		mock_cache.EXPECT().SetExWithTags(gomock.Any(), "go-svc-template:tenant:default:examples", exampleCachedBytes, time.Hour*24, "go-svc-template:tenant:default:tag:examples").Return(nil)
 
 		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
 		assert.NotNil(t, s)
 		assert.NoError(t, err)
 
-		examples, err := s.FetchExamples(context.Background())
+		examples, err := s.FetchExamples(context.Background(), false, nil)
 		assert.NotNil(t, examples)
 		assert.NoError(t, err)
 
@@ -338,3 +448,219 @@ func Test_GetExamples(t *testing.T) {
 		}
 	})
 }
+
+func Test_DeleteExample(t *testing.T) {
+	t.Run("ok - delete example", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
+		deleted := time.Now()
+
+		mock_database.EXPECT().DeleteExample(gomock.Any(), exampleID).Return(&entities_example_v1.Change{
+			Before: &entities_example_v1.Example{ID: exampleID},
+			After:  &entities_example_v1.Example{ID: exampleID, DeletedAt: &deleted},
+		}, nil)
+		mock_database.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Return(nil)
+		mock_cache.EXPECT().Del(gomock.Any(), fmt.Sprintf("go-svc-template:tenant:default:example:id:%v", exampleID)).Return(nil)
+		mock_cache.EXPECT().InvalidateTags(gomock.Any(), "go-svc-template:tenant:default:tag:examples").Return(nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		assert.NoError(t, s.DeleteExample(context.Background(), exampleID))
+	})
+	t.Run("ok - delete example again after the replica lag", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
+		deleted := time.Now()
+
+		mock_database.EXPECT().DeleteExample(gomock.Any(), exampleID).Return(&entities_example_v1.Change{
+			Before: &entities_example_v1.Example{ID: exampleID},
+			After:  &entities_example_v1.Example{ID: exampleID, DeletedAt: &deleted},
+		}, nil)
+		mock_database.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Return(nil)
+		mock_cache.EXPECT().Del(gomock.Any(), fmt.Sprintf("go-svc-template:tenant:default:example:id:%v", exampleID)).Return(nil).Times(2)
+
+		invalidated := make(chan struct{}, 2)
+		mock_cache.EXPECT().InvalidateTags(gomock.Any(), "go-svc-template:tenant:default:tag:examples").DoAndReturn(func(ctx context.Context, tags ...string) error {
+			invalidated <- struct{}{}
+			return nil
+		}).Times(2)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+		s.SetReplicaLag(10 * time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		assert.NoError(t, s.DeleteExample(ctx, exampleID))
+		cancel()
+
+		for i := 0; i < 2; i++ {
+			select {
+			case <-invalidated:
+			case <-time.After(time.Second):
+				t.Fatal("examples cache not invalidated again")
+			}
+		}
+	})
+	t.Run("nok - delete example denied by policy", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
+		deleted := time.Now()
+
+		mock_database.EXPECT().DeleteExample(gomock.Any(), exampleID).Return(&entities_example_v1.Change{
+			Before: &entities_example_v1.Example{ID: exampleID, Description: "not yours"},
+			After:  &entities_example_v1.Example{ID: exampleID, Description: "not yours", DeletedAt: &deleted},
+		}, nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		s.SetPolicy(func(ctx context.Context, action string, resource interface{}) error {
+			example, ok := resource.(*entities_example_v1.Example)
+			if !ok || example.Description == "not yours" {
+				return errors.NewForbiddenError("denied")
+			}
+			return nil
+		})
+
+		assert.True(t, errors.IsForbiddenError(s.DeleteExample(context.Background(), exampleID)))
+	})
+	t.Run("nok - delete unknown example", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		mock_database.EXPECT().DeleteExample(gomock.Any(), "exmp_unknown").Return(nil, errors.NewNotFoundError("error"))
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		assert.True(t, errors.IsNotFoundError(s.DeleteExample(context.Background(), "exmp_unknown")))
+	})
+}
+
+func Test_RestoreExample(t *testing.T) {
+	t.Run("ok - restore example", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		exampleID := constants.GenerateDataPrefixWithULID(constants.Example)
+
+		deleted := time.Now()
+
+		mock_database.EXPECT().RestoreExample(gomock.Any(), exampleID).Return(&entities_example_v1.Change{
+			Before: &entities_example_v1.Example{ID: exampleID, Description: "hello world !", DeletedAt: &deleted},
+			After:  &entities_example_v1.Example{ID: exampleID, Description: "hello world !"},
+		}, nil)
+		mock_database.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Return(nil)
+		mock_cache.EXPECT().Del(gomock.Any(), fmt.Sprintf("go-svc-template:tenant:acme:example:id:%v", exampleID)).Return(nil)
+		mock_cache.EXPECT().InvalidateTags(gomock.Any(), "go-svc-template:tenant:acme:tag:examples").Return(nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		example, err := s.RestoreExample(pkg_tenant.WithTenant(context.Background(), "acme"), exampleID)
+		assert.NotNil(t, example)
+		assert.NoError(t, err)
+
+		assert.Equal(t, exampleID, example.ID)
+		assert.Nil(t, example.DeletedAt)
+	})
+}
+
+func Test_PurgeDeletedExamples(t *testing.T) {
+	t.Run("ok - purge deleted examples of every tenant", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		deletedBefore := time.Now().Add(-time.Hour)
+
+		mock_database.EXPECT().PurgeExamples(gomock.Any(), deletedBefore, 100).Return([]*entities_example_v1.PurgedExample{
+			{ID: "exmp_1", TenantID: "default"},
+			{ID: "exmp_2", TenantID: "acme"},
+		}, nil)
+		mock_database.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		mock_cache.EXPECT().Del(gomock.Any(), "go-svc-template:tenant:default:example:id:exmp_1").Return(nil)
+		mock_cache.EXPECT().Del(gomock.Any(), "go-svc-template:tenant:acme:example:id:exmp_2").Return(nil)
+		mock_cache.EXPECT().InvalidateTags(gomock.Any(), "go-svc-template:tenant:default:tag:examples").Return(nil)
+		mock_cache.EXPECT().InvalidateTags(gomock.Any(), "go-svc-template:tenant:acme:tag:examples").Return(nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		purged, err := s.PurgeDeletedExamples(context.Background(), deletedBefore, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, purged)
+	})
+	t.Run("nok - purge deleted examples", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		mock_database.EXPECT().PurgeExamples(gomock.Any(), gomock.Any(), 100).Return(nil, errors.NewInternalServerError("error"))
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		purged, err := s.PurgeDeletedExamples(context.Background(), time.Now(), 100)
+		assert.Error(t, err)
+		assert.Zero(t, purged)
+	})
+}
+
+func Test_ExportExamples(t *testing.T) {
+	t.Run("ok - export examples without caching them", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		filter := entities_example_v1.ExportFilter{CreatedAfter: time.Now().Add(-time.Hour)}
+
+		mock_database.EXPECT().ExportExamples(gomock.Any(), filter, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, filter entities_example_v1.ExportFilter, fn func(*entities_example_v1.Example) error) error {
+				for _, id := range []string{"exmp_1", "exmp_2"} {
+					if err := fn(&entities_example_v1.Example{ID: id}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		ids := make([]string, 0)
+		err = s.ExportExamples(context.Background(), filter, func(example *entities_example_v1.Example) error {
+			ids = append(ids, example.ID)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"exmp_1", "exmp_2"}, ids)
+	})
+}
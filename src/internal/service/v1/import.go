@@ -0,0 +1,297 @@
+package service_v1
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	entities_audit_v1 "github.com/teyz/go-svc-template/internal/entities/audit/v1"
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	entities_import_v1 "github.com/teyz/go-svc-template/internal/entities/import/v1"
+	pkg_audit "github.com/teyz/go-svc-template/pkg/audit"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	pkg_tenant "github.com/teyz/go-svc-template/pkg/tenant"
+)
+
+const (
+	// ImportMaxBytes is the maximum size of an uploaded file.
+	ImportMaxBytes = 10 << 20
+	// ImportMaxRows is the maximum number of rows of an uploaded file.
+	ImportMaxRows = 100000
+
+	// importChunkSize is the number of rows applied in a single transaction.
+	importChunkSize = 500
+	// importStaleAfter is how long an import can stay processing without
+	// progress before another instance takes it over.
+	importStaleAfter = 5 * time.Minute
+)
+
+// errors reported on the rows of an import
+const (
+	importErrorDescriptionRequired = "description is required"
+	importErrorMalformedRow        = "malformed row"
+)
+
+// importRow is a row of an uploaded file, message being set when it is invalid.
+type importRow struct {
+	description string
+	message     string
+}
+
+// parseImport reads the rows of an uploaded file. The file is rejected as a
+// whole when its format is unknown or its structure is broken, invalid rows
+// are only reported.
+func parseImport(format string, data []byte) ([]importRow, error) {
+	switch format {
+	case entities_import_v1.FormatCSV:
+		return parseImportCSV(data)
+	case entities_import_v1.FormatNDJSON:
+		return parseImportNDJSON(data)
+	default:
+		return nil, errors.NewBadRequestError(fmt.Sprintf("service.v1.service.parseImport: unknown import format %q", format))
+	}
+}
+
+// parseImportCSV reads a CSV file whose header holds a description column.
+func parseImportCSV(data []byte) ([]importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("service.v1.service.parseImportCSV: unable to read header: %v", err.Error()))
+	}
+
+	// the header may start with the byte order mark written by spreadsheets
+	column := -1
+	for i, name := range header {
+		if strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")) == "description" {
+			column = i
+			break
+		}
+	}
+	if column < 0 {
+		return nil, errors.NewBadRequestError("service.v1.service.parseImportCSV: header has no description column")
+	}
+
+	rows := make([]importRow, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		parseError, malformed := err.(*csv.ParseError)
+		switch {
+		case malformed:
+			rows = append(rows, importRow{message: fmt.Sprintf("%s: %v", importErrorMalformedRow, parseError.Err)})
+		case err != nil:
+			return nil, errors.NewBadRequestError(fmt.Sprintf("service.v1.service.parseImportCSV: unable to read row: %v", err.Error()))
+		case column >= len(record):
+			rows = append(rows, importRow{message: importErrorDescriptionRequired})
+		default:
+			rows = append(rows, newImportRow(record[column]))
+		}
+	}
+
+	return rows, nil
+}
+
+// parseImportNDJSON reads an NDJSON file of {"description": "..."} objects,
+// blank lines being skipped.
+func parseImportNDJSON(data []byte) ([]importRow, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), ImportMaxBytes)
+
+	rows := make([]importRow, 0)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var item struct {
+			Description string `json:"description"`
+		}
+		if err := json.Unmarshal(line, &item); err != nil {
+			rows = append(rows, importRow{message: fmt.Sprintf("%s: %v", importErrorMalformedRow, err.Error())})
+			continue
+		}
+
+		rows = append(rows, newImportRow(item.Description))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("service.v1.service.parseImportNDJSON: unable to read line: %v", err.Error()))
+	}
+
+	return rows, nil
+}
+
+func newImportRow(description string) importRow {
+	if description == "" {
+		return importRow{message: importErrorDescriptionRequired}
+	}
+
+	return importRow{description: description}
+}
+
+// CreateImport validates an uploaded file and stores it to be processed in the
+// background, the returned import is pending.
+func (s *service) CreateImport(ctx context.Context, format string, data []byte) (*entities_import_v1.Import, error) {
+	if err := s.policy(ctx, ScopeExamplesWrite, nil); err != nil {
+		return nil, err
+	}
+
+	if len(data) > ImportMaxBytes {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("service.v1.service.CreateImport: file exceeds %d bytes", ImportMaxBytes))
+	}
+
+	rows, err := parseImport(format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 || len(rows) > ImportMaxRows {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("service.v1.service.CreateImport: a file holds from 1 to %d rows, got %d", ImportMaxRows, len(rows)))
+	}
+
+	imp := &entities_import_v1.Import{
+		Format:    format,
+		TotalRows: len(rows),
+		Actor:     pkg_audit.MetadataFromContext(ctx).Actor,
+		Data:      data,
+	}
+
+	if err := s.store.CreateImport(ctx, imp); err != nil {
+		return nil, err
+	}
+
+	return imp, nil
+}
+
+func (s *service) GetImport(ctx context.Context, id string) (*entities_import_v1.Import, error) {
+	imp, err := s.store.GetImportByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy(ctx, ScopeExamplesWrite, imp); err != nil {
+		return nil, err
+	}
+
+	return imp, nil
+}
+
+// FetchImportErrors returns the rows rejected so far by an import.
+func (s *service) FetchImportErrors(ctx context.Context, id string) ([]*entities_import_v1.RowError, error) {
+	imp, err := s.store.GetImportByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.policy(ctx, ScopeExamplesWrite, imp); err != nil {
+		return nil, err
+	}
+
+	return s.store.FetchImportErrors(ctx, id)
+}
+
+// ProcessNextImport processes the next pending import of any tenant, on behalf
+// of the tenant and actor who uploaded it, and reports whether there was one.
+// An import interrupted by an error is resumed from its last chunk once stale.
+func (s *service) ProcessNextImport(ctx context.Context) (bool, error) {
+	imp, err := s.store.ClaimImport(ctx, time.Now().Add(-importStaleAfter))
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	ctx = pkg_tenant.WithTenant(ctx, imp.TenantID)
+	ctx = pkg_audit.WithMetadata(ctx, pkg_audit.Metadata{Actor: imp.Actor})
+
+	rows, err := parseImport(imp.Format, imp.Data)
+	if err != nil {
+		log.Error().Err(err).
+			Str("id", imp.ID).
+			Msg("service.v1.service.ProcessNextImport: unable to parse import")
+		return true, s.store.CompleteImport(ctx, imp.ID, entities_import_v1.StatusFailed, "unable to parse file")
+	}
+
+	for start := imp.ProcessedRows; start < len(rows); start += importChunkSize {
+		end := start + importChunkSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		if err := s.applyImportChunk(ctx, imp.ID, rows, start, end); err != nil {
+			// the import was claimed again by another instance while stale
+			if errors.IsOutdatedResourceError(err) {
+				return true, nil
+			}
+			return true, err
+		}
+	}
+
+	if err := s.store.CompleteImport(ctx, imp.ID, entities_import_v1.StatusCompleted, ""); err != nil {
+		return true, err
+	}
+
+	log.Info().
+		Str("id", imp.ID).
+		Str("tenant", imp.TenantID).
+		Int("rows", len(rows)).
+		Msg("service.v1.service.ProcessNextImport: import completed")
+
+	return true, nil
+}
+
+// applyImportChunk creates the valid examples of rows[start:end] and records
+// the invalid rows, then audits the chunk and invalidates the cache once.
+func (s *service) applyImportChunk(ctx context.Context, id string, rows []importRow, start int, end int) error {
+	descriptions := make([]string, 0, end-start)
+	rowErrors := make([]*entities_import_v1.RowError, 0)
+
+	for index := start; index < end; index++ {
+		if rows[index].message != "" {
+			rowErrors = append(rowErrors, &entities_import_v1.RowError{Row: index + 1, Message: rows[index].message})
+			continue
+		}
+		descriptions = append(descriptions, rows[index].description)
+	}
+
+	var examples []*entities_example_v1.Example
+	err := s.store.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		examples, err = s.store.ApplyImportChunk(ctx, id, start, end, descriptions, s.searchLanguage(), rowErrors)
+		if err != nil {
+			return err
+		}
+
+		created := make(map[string]auditChange, len(examples))
+		for _, example := range examples {
+			created[example.ID] = auditChange{after: example}
+		}
+
+		return s.recordAudits(ctx, entities_audit_v1.ActionCreate, entities_audit_v1.ResourceTypeExample, created)
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(examples) == 0 {
+		return nil
+	}
+
+	s.invalidateExamples(ctx, nil)
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package service_v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	database_mocks "github.com/teyz/go-svc-template/internal/database/mocks"
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	entities_import_v1 "github.com/teyz/go-svc-template/internal/entities/import/v1"
+	cache_mocks "github.com/teyz/go-svc-template/pkg/cache/mocks"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_ParseImport(t *testing.T) {
+	t.Run("ok - parse csv", func(t *testing.T) {
+		rows, err := parseImport(entities_import_v1.FormatCSV, []byte("\ufeffid,description\n1,first\n2,\n3\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, []importRow{
+			{description: "first"},
+			{message: importErrorDescriptionRequired},
+			{message: importErrorDescriptionRequired},
+		}, rows)
+	})
+	t.Run("ok - parse ndjson", func(t *testing.T) {
+		rows, err := parseImport(entities_import_v1.FormatNDJSON, []byte("{\"description\":\"first\"}\n\n{\"description\":\"\"}\nnot json\n"))
+		assert.NoError(t, err)
+		assert.Len(t, rows, 3)
+		assert.Equal(t, importRow{description: "first"}, rows[0])
+		assert.Equal(t, importRow{message: importErrorDescriptionRequired}, rows[1])
+		assert.Contains(t, rows[2].message, importErrorMalformedRow)
+	})
+	t.Run("nok - parse csv without description column", func(t *testing.T) {
+		rows, err := parseImport(entities_import_v1.FormatCSV, []byte("id,name\n1,first\n"))
+		assert.Nil(t, rows)
+		assert.True(t, errors.IsBadRequestError(err))
+	})
+	t.Run("nok - parse unknown format", func(t *testing.T) {
+		rows, err := parseImport("xml", []byte("<examples/>"))
+		assert.Nil(t, rows)
+		assert.True(t, errors.IsBadRequestError(err))
+	})
+}
+
+func Test_CreateImport(t *testing.T) {
+	t.Run("ok - create import", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		mock_database.EXPECT().CreateImport(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, imp *entities_import_v1.Import) error {
+			imp.ID = "impt_1"
+			imp.Status = entities_import_v1.StatusPending
+			return nil
+		})
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		imp, err := s.CreateImport(context.Background(), entities_import_v1.FormatCSV, []byte("description\nfirst\nsecond\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, "impt_1", imp.ID)
+		assert.Equal(t, 2, imp.TotalRows)
+		assert.Equal(t, "anonymous", imp.Actor)
+	})
+	t.Run("nok - create import of an empty file", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		imp, err := s.CreateImport(context.Background(), entities_import_v1.FormatNDJSON, []byte("\n"))
+		assert.Nil(t, imp)
+		assert.True(t, errors.IsBadRequestError(err))
+	})
+}
+
+func Test_ProcessNextImport(t *testing.T) {
+	t.Run("ok - process import from its last chunk", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		mock_database.EXPECT().ClaimImport(gomock.Any(), gomock.Any()).Return(&entities_import_v1.Import{
+			ID:            "impt_1",
+			TenantID:      "acme",
+			Actor:         "user",
+			Format:        entities_import_v1.FormatCSV,
+			ProcessedRows: 1,
+			Data:          []byte("description\nfirst\nsecond\n\n"),
+		}, nil)
+		mock_database.EXPECT().ApplyImportChunk(gomock.Any(), "impt_1", 1, 2, []string{"second"}, "english", []*entities_import_v1.RowError{}).Return([]*entities_example_v1.Example{
+			{ID: "exmp_2", Description: "second"},
+		}, nil)
+		mock_database.EXPECT().CreateAuditEntries(gomock.Any(), gomock.Len(1)).Return(nil)
+		mock_cache.EXPECT().InvalidateTags(gomock.Any(), "go-svc-template:tenant:acme:tag:examples").Return(nil)
+		mock_database.EXPECT().CompleteImport(gomock.Any(), "impt_1", entities_import_v1.StatusCompleted, "").Return(nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		processed, err := s.ProcessNextImport(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, processed)
+	})
+	t.Run("ok - no import to process", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		mock_database.EXPECT().ClaimImport(gomock.Any(), gomock.Any()).Return(nil, errors.NewNotFoundError("error"))
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		processed, err := s.ProcessNextImport(context.Background())
+		assert.NoError(t, err)
+		assert.False(t, processed)
+	})
+	t.Run("ok - stop processing import taken over by another instance", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		mock_database.EXPECT().ClaimImport(gomock.Any(), gomock.Any()).Return(&entities_import_v1.Import{
+			ID:     "impt_1",
+			Format: entities_import_v1.FormatNDJSON,
+			Data:   []byte("{\"description\":\"first\"}\n"),
+		}, nil)
+		mock_database.EXPECT().ApplyImportChunk(gomock.Any(), "impt_1", 0, 1, []string{"first"}, "english", gomock.Any()).Return(nil, errors.NewOutdatedResourceError("error"))
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		processed, err := s.ProcessNextImport(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, processed)
+	})
+	t.Run("nok - process import", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+		expectTx(mock_database)
+
+		mock_database.EXPECT().ClaimImport(gomock.Any(), gomock.Any()).Return(&entities_import_v1.Import{
+			ID:     "impt_1",
+			Format: entities_import_v1.FormatNDJSON,
+			Data:   []byte("{\"description\":\"first\"}\n"),
+		}, nil)
+		mock_database.EXPECT().ApplyImportChunk(gomock.Any(), "impt_1", 0, 1, []string{"first"}, "english", gomock.Any()).Return(nil, errors.NewInternalServerError("error"))
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		processed, err := s.ProcessNextImport(context.Background())
+		assert.Error(t, err)
+		assert.True(t, processed)
+	})
+}
+
+func Test_GetImport(t *testing.T) {
+	t.Run("ok - get import", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		mock_database.EXPECT().GetImportByID(gomock.Any(), "imp_1").Return(&entities_import_v1.Import{ID: "imp_1", Actor: "user-1"}, nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		imp, err := s.GetImport(context.Background(), "imp_1")
+		assert.NoError(t, err)
+		assert.Equal(t, "imp_1", imp.ID)
+	})
+	t.Run("nok - get import denied by policy", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		mock_database.EXPECT().GetImportByID(gomock.Any(), "imp_1").Return(&entities_import_v1.Import{ID: "imp_1", Actor: "user-2"}, nil).Times(2)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		s.SetPolicy(func(ctx context.Context, action string, resource interface{}) error {
+			if imp, ok := resource.(*entities_import_v1.Import); !ok || imp.Actor != "user-1" {
+				return errors.NewForbiddenError("denied")
+			}
+			return nil
+		})
+
+		imp, err := s.GetImport(context.Background(), "imp_1")
+		assert.Nil(t, imp)
+		assert.True(t, errors.IsForbiddenError(err))
+
+		rowErrors, err := s.FetchImportErrors(context.Background(), "imp_1")
+		assert.Nil(t, rowErrors)
+		assert.True(t, errors.IsForbiddenError(err))
+	})
+}
@@ -3,32 +3,103 @@ package service_v1
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/teyz/go-svc-template/internal/database"
+	pkg_auth "github.com/teyz/go-svc-template/pkg/auth"
 	pkg_cache "github.com/teyz/go-svc-template/pkg/cache"
 )
 
 const (
-	exampleCacheDuration = time.Hour * 24
+	exampleCacheDuration  = time.Hour * 24
+	exampleSearchLanguage = "english"
 )
 
-func generateExampleCacheKeyWithID(id string) string {
-	return fmt.Sprintf("go-svc-template:example:id:%v", id)
+// actions on examples, also used as the scopes granting them
+const (
+	ScopeExamplesRead  = "examples:read"
+	ScopeExamplesWrite = "examples:write"
+	ScopeAPIKeysAdmin  = "api-keys:admin"
+	ScopeAuditRead     = "audit:read"
+)
+
+// example cache keys are prefixed by the tenant so that no data leaks across tenants
+
+func generateExampleCacheKeyWithID(tenant string, id string) string {
+	return fmt.Sprintf("go-svc-template:tenant:%v:example:id:%v", tenant, id)
+}
+
+func generateExamplesCacheKey(tenant string, includeDeleted bool) string {
+	if includeDeleted {
+		return fmt.Sprintf("go-svc-template:tenant:%v:examples:with_deleted", tenant)
+	}
+
+	return fmt.Sprintf("go-svc-template:tenant:%v:examples", tenant)
+}
+
+// withFieldsCacheKey suffixes the cache key of examples with the fields they are
+// restricted to, if any.
+func withFieldsCacheKey(key string, fields []string) string {
+	if len(fields) == 0 {
+		return key
+	}
+
+	return fmt.Sprintf("%v:fields:%v", key, strings.Join(fields, ","))
 }
 
-func generateExamplesCacheKey() string {
-	return "go-svc-template:examples"
+func generateExamplesCacheTag(tenant string) string {
+	return fmt.Sprintf("go-svc-template:tenant:%v:tag:examples", tenant)
 }
 
 type service struct {
-	store database.Database
-	cache pkg_cache.Cache
+	store         database.Database
+	cache         pkg_cache.Cache
+	cacheDuration atomic.Int64
+	replicaLag    atomic.Int64
+	language      atomic.Value
+	policy        pkg_auth.Policy
 }
 
 func NewExampleStoreService(ctx context.Context, store database.Database, cache pkg_cache.Cache) (*service, error) {
-	return &service{
-		store: store,
-		cache: cache,
-	}, nil
+	s := &service{
+		store:  store,
+		cache:  cache,
+		policy: pkg_auth.AllowAll,
+	}
+	s.cacheDuration.Store(int64(exampleCacheDuration))
+	s.language.Store(exampleSearchLanguage)
+
+	return s, nil
+}
+
+// SetCacheDuration changes how long examples are cached, it is safe to call while serving.
+func (s *service) SetCacheDuration(duration time.Duration) {
+	s.cacheDuration.Store(int64(duration))
+}
+
+// SetReplicaLag sets how long replicas may take to apply a mutation, the cache
+// is invalidated again after it. It is zero by default, for reads served by the primary.
+func (s *service) SetReplicaLag(lag time.Duration) {
+	s.replicaLag.Store(int64(lag))
+}
+
+// SetSearchLanguage changes the text search configuration of Postgres used to
+// index new examples and to parse searches, it is safe to call while serving.
+func (s *service) SetSearchLanguage(language string) {
+	s.language.Store(language)
+}
+
+// SetPolicy sets the policy checking resource-level access, every action is allowed by default.
+func (s *service) SetPolicy(policy pkg_auth.Policy) {
+	s.policy = policy
+}
+
+func (s *service) cacheTTL() time.Duration {
+	return time.Duration(s.cacheDuration.Load())
+}
+
+func (s *service) searchLanguage() string {
+	return s.language.Load().(string)
 }
@@ -2,12 +2,50 @@ package service_v1
 
 import (
 	"context"
+	"time"
 
+	entities_apikey_v1 "github.com/teyz/go-svc-template/internal/entities/apikey/v1"
+	entities_audit_v1 "github.com/teyz/go-svc-template/internal/entities/audit/v1"
 	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	entities_import_v1 "github.com/teyz/go-svc-template/internal/entities/import/v1"
+	pkg_auth "github.com/teyz/go-svc-template/pkg/auth"
 )
 
 type ExampleStoreService interface {
 	CreateExample(ctx context.Context, description string) (*entities_example_v1.Example, error)
-	FetchExamples(ctx context.Context) ([]*entities_example_v1.Example, error)
-	GetExampleByID(ctx context.Context, id string) (*entities_example_v1.Example, error)
+	FetchExamples(ctx context.Context, includeDeleted bool, fields []string) ([]*entities_example_v1.Example, error)
+	GetExampleByID(ctx context.Context, id string, fields []string) (*entities_example_v1.Example, error)
+	DeleteExample(ctx context.Context, id string) error
+	RestoreExample(ctx context.Context, id string) (*entities_example_v1.Example, error)
+	SearchExamples(ctx context.Context, text string, cursor string, limit int) ([]*entities_example_v1.SearchResult, string, error)
+	CreateExamples(ctx context.Context, descriptions []string, mode string) (*entities_example_v1.BatchResult, error)
+	UpdateExamples(ctx context.Context, examples []*entities_example_v1.Example, mode string) (*entities_example_v1.BatchResult, error)
+	DeleteExamples(ctx context.Context, ids []string, mode string) (*entities_example_v1.BatchResult, error)
+	ExportExamples(ctx context.Context, filter entities_example_v1.ExportFilter, fn func(*entities_example_v1.Example) error) error
+}
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, name string, scopes []string, expiresAt *time.Time) (*entities_apikey_v1.APIKey, string, error)
+	FetchAPIKeys(ctx context.Context) ([]*entities_apikey_v1.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+	pkg_auth.APIKeyResolver
+}
+
+type AuditService interface {
+	FetchAuditEntries(ctx context.Context, filter entities_audit_v1.Filter) ([]*entities_audit_v1.Entry, error)
+}
+
+type ImportService interface {
+	CreateImport(ctx context.Context, format string, data []byte) (*entities_import_v1.Import, error)
+	GetImport(ctx context.Context, id string) (*entities_import_v1.Import, error)
+	FetchImportErrors(ctx context.Context, id string) ([]*entities_import_v1.RowError, error)
+}
+
+// Service groups every use case of the service, it is implemented by the service
+// returned by NewExampleStoreService.
+type Service interface {
+	ExampleStoreService
+	APIKeyService
+	AuditService
+	ImportService
 }
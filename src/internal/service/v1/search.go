@@ -0,0 +1,91 @@
+package service_v1
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	"github.com/teyz/go-svc-template/pkg/errors"
+)
+
+const (
+	searchDefaultLimit = 20
+	searchMaxLimit     = 100
+)
+
+// SearchExamples returns a page of the examples whose description matches text,
+// most relevant first, with the cursor of the next page, empty on the last one.
+func (s *service) SearchExamples(ctx context.Context, text string, cursor string, limit int) ([]*entities_example_v1.SearchResult, string, error) {
+	if err := s.policy(ctx, ScopeExamplesRead, nil); err != nil {
+		return nil, "", err
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return nil, "", errors.NewBadRequestError("service.v1.service.SearchExamples: search text is empty")
+	}
+
+	query := entities_example_v1.SearchQuery{
+		Text:     text,
+		Language: s.searchLanguage(),
+		Limit:    limit,
+	}
+	if query.Limit <= 0 {
+		query.Limit = searchDefaultLimit
+	}
+	if query.Limit > searchMaxLimit {
+		query.Limit = searchMaxLimit
+	}
+
+	if cursor != "" {
+		after, err := decodeSearchCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query.After = after
+	}
+
+	// one more result tells whether there is a next page
+	query.Limit++
+	results, err := s.store.SearchExamples(ctx, query)
+	if err != nil {
+		return nil, "", err
+	}
+	query.Limit--
+
+	if len(results) <= query.Limit {
+		return results, "", nil
+	}
+
+	results = results[:query.Limit]
+	last := results[len(results)-1]
+
+	return results, encodeSearchCursor(&entities_example_v1.SearchCursor{Rank: last.Rank, ID: last.ID}), nil
+}
+
+func encodeSearchCursor(cursor *entities_example_v1.SearchCursor) string {
+	bytes, err := json.Marshal(cursor)
+	if err != nil {
+		log.Error().Err(err).
+			Msg("service.v1.encodeSearchCursor: unable to marshal search cursor")
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+func decodeSearchCursor(cursor string) (*entities_example_v1.SearchCursor, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.NewBadRequestError("service.v1.decodeSearchCursor: invalid search cursor")
+	}
+
+	var after *entities_example_v1.SearchCursor
+	if err := json.Unmarshal(bytes, &after); err != nil || after == nil || after.ID == "" {
+		return nil, errors.NewBadRequestError("service.v1.decodeSearchCursor: invalid search cursor")
+	}
+
+	return after, nil
+}
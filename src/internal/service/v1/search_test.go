@@ -0,0 +1,96 @@
+package service_v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	database_mocks "github.com/teyz/go-svc-template/internal/database/mocks"
+	entities_example_v1 "github.com/teyz/go-svc-template/internal/entities/example/v1"
+	cache_mocks "github.com/teyz/go-svc-template/pkg/cache/mocks"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_SearchExamples(t *testing.T) {
+	t.Run("ok - search examples with a next page", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		mock_database.EXPECT().SearchExamples(gomock.Any(), entities_example_v1.SearchQuery{
+			Text:     "hello",
+			Language: "french",
+			Limit:    3,
+		}).Return([]*entities_example_v1.SearchResult{
+			{Example: entities_example_v1.Example{ID: "exmp_1"}, Rank: 0.5},
+			{Example: entities_example_v1.Example{ID: "exmp_2"}, Rank: 0.2},
+			{Example: entities_example_v1.Example{ID: "exmp_3"}, Rank: 0.1},
+		}, nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+		s.SetSearchLanguage("french")
+
+		results, cursor, err := s.SearchExamples(context.Background(), "hello", "", 2)
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.NotEmpty(t, cursor)
+
+		after, err := decodeSearchCursor(cursor)
+		assert.NoError(t, err)
+		assert.Equal(t, &entities_example_v1.SearchCursor{Rank: 0.2, ID: "exmp_2"}, after)
+	})
+	t.Run("ok - search examples after cursor on the last page", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		after := &entities_example_v1.SearchCursor{Rank: 0.2, ID: "exmp_2"}
+
+		mock_database.EXPECT().SearchExamples(gomock.Any(), entities_example_v1.SearchQuery{
+			Text:     "hello",
+			Language: "english",
+			After:    after,
+			Limit:    searchDefaultLimit + 1,
+		}).Return([]*entities_example_v1.SearchResult{
+			{Example: entities_example_v1.Example{ID: "exmp_3"}, Rank: 0.1},
+		}, nil)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		results, cursor, err := s.SearchExamples(context.Background(), "hello", encodeSearchCursor(after), 0)
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Empty(t, cursor)
+	})
+	t.Run("nok - search examples with an invalid cursor", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		results, _, err := s.SearchExamples(context.Background(), "hello", "not a cursor", 0)
+		assert.Nil(t, results)
+		assert.True(t, errors.IsBadRequestError(err))
+	})
+	t.Run("nok - search examples without text", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_database := database_mocks.NewMockDatabase(ctrl)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		s, err := NewExampleStoreService(context.Background(), mock_database, mock_cache)
+		assert.NotNil(t, s)
+		assert.NoError(t, err)
+
+		results, _, err := s.SearchExamples(context.Background(), "  ", "", 0)
+		assert.Nil(t, results)
+		assert.True(t, errors.IsBadRequestError(err))
+	})
+}
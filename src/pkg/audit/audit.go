@@ -0,0 +1,67 @@
+package pkg_audit
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+
+	pkg_auth "github.com/teyz/go-svc-template/pkg/auth"
+)
+
+// ActorAnonymous is the actor of calls made without an authenticated identity.
+const ActorAnonymous = "anonymous"
+
+// Metadata describes the request a mutation was made in.
+type Metadata struct {
+	Actor     string
+	RequestID string
+	IP        string
+}
+
+type metadataKey struct{}
+
+// WithMetadata returns a copy of ctx carrying the metadata of the request.
+func WithMetadata(ctx context.Context, metadata Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+// MetadataFromContext returns the metadata of the request, calls made outside of
+// a request, e.g. by the CLI, are anonymous.
+func MetadataFromContext(ctx context.Context) Metadata {
+	metadata, ok := ctx.Value(metadataKey{}).(Metadata)
+	if !ok || metadata.Actor == "" {
+		metadata.Actor = ActorAnonymous
+	}
+
+	return metadata
+}
+
+// Middleware stores the metadata of the request in the request context. The
+// actor is the authenticated caller, so it must run after the authentication
+// middleware, requests without one being anonymous whatever headers they send,
+// and the request ID is the one set by the RequestID middleware.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+
+			actor := ActorAnonymous
+			if claims, ok := pkg_auth.ClaimsFromContext(ctx); ok && claims.Subject != "" {
+				actor = claims.Subject
+			}
+
+			requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+			if requestID == "" {
+				requestID = c.Request().Header.Get(echo.HeaderXRequestID)
+			}
+
+			c.SetRequest(c.Request().WithContext(WithMetadata(ctx, Metadata{
+				Actor:     actor,
+				RequestID: requestID,
+				IP:        c.RealIP(),
+			})))
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package pkg_audit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	pkg_auth "github.com/teyz/go-svc-template/pkg/auth"
+)
+
+func serve(claims *pkg_auth.Claims, headers map[string]string) Metadata {
+	var metadata Metadata
+
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error {
+		metadata = MetadataFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	}, Middleware())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if claims != nil {
+		req = req.WithContext(pkg_auth.WithClaims(req.Context(), claims))
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	return metadata
+}
+
+func Test_Middleware(t *testing.T) {
+	t.Run("ok - actor from claims", func(t *testing.T) {
+		metadata := serve(&pkg_auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "user"}}, nil)
+
+		assert.Equal(t, "user", metadata.Actor)
+	})
+	t.Run("ok - anonymous without claims", func(t *testing.T) {
+		metadata := serve(nil, map[string]string{"X-Caller-ID": "admin"})
+
+		assert.Equal(t, ActorAnonymous, metadata.Actor)
+	})
+	t.Run("ok - anonymous without subject", func(t *testing.T) {
+		metadata := serve(&pkg_auth.Claims{}, nil)
+
+		assert.Equal(t, ActorAnonymous, metadata.Actor)
+	})
+	t.Run("ok - request id and ip", func(t *testing.T) {
+		metadata := serve(nil, map[string]string{
+			echo.HeaderXRequestID:    "request",
+			echo.HeaderXForwardedFor: "203.0.113.7",
+		})
+
+		assert.Equal(t, "request", metadata.RequestID)
+		assert.Equal(t, "203.0.113.7", metadata.IP)
+	})
+}
+
+func Test_MetadataFromContext(t *testing.T) {
+	t.Run("ok - anonymous outside of a request", func(t *testing.T) {
+		metadata := MetadataFromContext(context.Background())
+
+		assert.Equal(t, ActorAnonymous, metadata.Actor)
+		assert.Empty(t, metadata.RequestID)
+	})
+}
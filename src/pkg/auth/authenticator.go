@@ -0,0 +1,96 @@
+package pkg_auth
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ErrNoCredentials is returned by an Authenticator when the request carries none
+// of the credentials it handles, so that the next one can be tried.
+var ErrNoCredentials = errors.New("no credentials")
+
+// Authenticator authenticates a request and returns the claims of its caller.
+type Authenticator interface {
+	Authenticate(c echo.Context) (*Claims, error)
+}
+
+// BearerAuthenticator authenticates requests carrying a bearer JWT.
+type BearerAuthenticator struct {
+	verifier *Verifier
+}
+
+func NewBearerAuthenticator(verifier *Verifier) *BearerAuthenticator {
+	return &BearerAuthenticator{
+		verifier: verifier,
+	}
+}
+
+func (a *BearerAuthenticator) Authenticate(c echo.Context) (*Claims, error) {
+	scheme, token, ok := strings.Cut(c.Request().Header.Get(echo.HeaderAuthorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, ErrNoCredentials
+	}
+
+	return a.verifier.Verify(c.Request().Context(), token)
+}
+
+const (
+	HeaderSubject = "X-Auth-Subject"
+	HeaderScopes  = "X-Auth-Scopes"
+	HeaderRoles   = "X-Auth-Roles"
+	HeaderTenant  = "X-Auth-Tenant"
+)
+
+// HeaderAuthenticator trusts the identity set in headers by a gateway that
+// already authenticated the caller. It must only be used when the service cannot
+// be reached without going through that gateway.
+type HeaderAuthenticator struct{}
+
+func NewHeaderAuthenticator() *HeaderAuthenticator {
+	return &HeaderAuthenticator{}
+}
+
+func (a *HeaderAuthenticator) Authenticate(c echo.Context) (*Claims, error) {
+	header := c.Request().Header
+
+	subject := header.Get(HeaderSubject)
+	if subject == "" {
+		return nil, ErrNoCredentials
+	}
+
+	claims := &Claims{
+		Scope:    strings.Join(strings.FieldsFunc(header.Get(HeaderScopes), isListSeparator), " "),
+		Roles:    strings.FieldsFunc(header.Get(HeaderRoles), isListSeparator),
+		TenantID: header.Get(HeaderTenant),
+	}
+	claims.Subject = subject
+
+	return claims, nil
+}
+
+func isListSeparator(r rune) bool {
+	return r == ',' || r == ' '
+}
+
+// NewAuthenticators returns the authenticators enabled by the config, none when
+// authentication is disabled. API keys are resolved by apiKeys.
+func NewAuthenticators(cfg *AuthConfig, apiKeys APIKeyResolver) []Authenticator {
+	if cfg.Disabled {
+		return nil
+	}
+
+	authenticators := make([]Authenticator, 0)
+	if cfg.JWTSecret != "" || cfg.JWKSURL != "" || cfg.JWKSFile != "" {
+		authenticators = append(authenticators, NewBearerAuthenticator(NewVerifier(cfg)))
+	}
+	if cfg.APIKeys && apiKeys != nil {
+		authenticators = append(authenticators, NewAPIKeyAuthenticator(apiKeys))
+	}
+	if cfg.TrustedHeaders {
+		authenticators = append(authenticators, NewHeaderAuthenticator())
+	}
+
+	return authenticators
+}
@@ -0,0 +1,47 @@
+package pkg_auth
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims are the claims of an authenticated caller.
+type Claims struct {
+	jwt.RegisteredClaims
+	// Scope is the space separated list of granted scopes, as in OAuth 2.0
+	Scope string   `json:"scope,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+	// TenantID binds the caller to a single tenant
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// Scopes returns the granted scopes.
+func (c *Claims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+// HasScope reports whether scope was granted.
+func (c *Claims) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes(), scope)
+}
+
+// HasRole reports whether the caller has role.
+func (c *Claims) HasRole(role string) bool {
+	return slices.Contains(c.Roles, role)
+}
+
+type claimsKey struct{}
+
+// WithClaims returns a copy of ctx carrying the claims of the caller.
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims of the caller, if authenticated.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
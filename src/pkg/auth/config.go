@@ -0,0 +1,45 @@
+package pkg_auth
+
+import (
+	"errors"
+	"time"
+)
+
+type AuthConfig struct {
+	// Disabled turns authentication off, it must only be used for local development
+	Disabled bool `env:"AUTH_DISABLED" envDefault:"false"`
+
+	// JWTSecret verifies HS256 tokens
+	JWTSecret string `env:"AUTH_JWT_SECRET" secret:"true"`
+	// JWKSURL or JWKSFile hold the public keys verifying RS256 and ES256 tokens
+	JWKSURL             string        `env:"AUTH_JWKS_URL"`
+	JWKSFile            string        `env:"AUTH_JWKS_FILE_PATH"`
+	JWKSRefreshInterval time.Duration `env:"AUTH_JWKS_REFRESH_INTERVAL" envDefault:"15m" validate:"min=1m"`
+
+	// APIKeys accepts the API keys stored by the service in the X-API-Key header
+	APIKeys bool `env:"AUTH_API_KEYS" envDefault:"false"`
+
+	// TrustedHeaders reads the caller identity from the X-Auth-* headers set by a gateway
+	TrustedHeaders bool `env:"AUTH_TRUSTED_HEADERS" envDefault:"false"`
+
+	Issuer   string        `env:"AUTH_ISSUER"`
+	Audience string        `env:"AUTH_AUDIENCE"`
+	Leeway   time.Duration `env:"AUTH_LEEWAY" envDefault:"30s" validate:"min=0s"`
+}
+
+// Validate checks that tokens can be verified when authentication is enabled.
+func (cfg *AuthConfig) Validate() error {
+	if cfg.Disabled {
+		return nil
+	}
+
+	if cfg.JWTSecret == "" && cfg.JWKSURL == "" && cfg.JWKSFile == "" && !cfg.APIKeys && !cfg.TrustedHeaders {
+		return errors.New("env: one of AUTH_JWT_SECRET, AUTH_JWKS_URL, AUTH_JWKS_FILE_PATH, AUTH_API_KEYS or AUTH_TRUSTED_HEADERS is required unless AUTH_DISABLED is set")
+	}
+
+	if cfg.JWKSURL != "" && cfg.JWKSFile != "" {
+		return errors.New("env: AUTH_JWKS_URL and AUTH_JWKS_FILE_PATH are mutually exclusive")
+	}
+
+	return nil
+}
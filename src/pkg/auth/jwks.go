@@ -0,0 +1,225 @@
+package pkg_auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/singleflight"
+)
+
+// minRefreshInterval limits how often an unknown key id or a failed refresh
+// triggers a refresh.
+const minRefreshInterval = time.Minute
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// KeySet caches the public keys of a JWKS read from a URL or a file. Keys are
+// refreshed periodically and when a token references an unknown key id, so
+// that rotations are picked up without restart.
+type KeySet struct {
+	url             string
+	file            string
+	refreshInterval time.Duration
+	client          *http.Client
+
+	// refreshes collapses the refreshes of concurrent lookups into one
+	refreshes singleflight.Group
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	refreshedAt time.Time
+	attemptedAt time.Time
+}
+
+func NewKeySet(url, file string, refreshInterval time.Duration) *KeySet {
+	return &KeySet{
+		url:             url,
+		file:            file,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Key returns the public key with the given id.
+func (ks *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	stale := time.Since(ks.refreshedAt) >= ks.refreshInterval
+	canRefresh := time.Since(ks.attemptedAt) >= minRefreshInterval
+	ks.mu.RUnlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+
+	if canRefresh {
+		// the refresh is shared with concurrent lookups, it must not be canceled
+		// along with the request that started it
+		_, err, _ := ks.refreshes.Do("refresh", func() (interface{}, error) {
+			return nil, ks.Refresh(context.WithoutCancel(ctx))
+		})
+		if err != nil {
+			log.Error().Err(err).
+				Msg("pkg_auth.KeySet.Key: unable to refresh key set")
+		}
+	}
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, ok = ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id: %s", kid)
+	}
+
+	return key, nil
+}
+
+// Refresh reads the key set again, the known keys are kept on failure.
+func (ks *KeySet) Refresh(ctx context.Context) error {
+	keys, err := ks.fetch(ctx)
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	// failures count as attempts too, so that an unavailable key set is not
+	// requested by every lookup
+	ks.attemptedAt = time.Now()
+	if err != nil {
+		return err
+	}
+
+	ks.keys = keys
+	ks.refreshedAt = ks.attemptedAt
+
+	return nil
+}
+
+func (ks *KeySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	content, err := ks.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &jsonWebKeySet{}
+	if err := json.Unmarshal(content, set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			log.Error().Err(err).
+				Str("kid", jwk.Kid).
+				Msg("pkg_auth.KeySet.fetch: skipping invalid key")
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (ks *KeySet) read(ctx context.Context) ([]byte, error) {
+	if ks.file != "" {
+		return os.ReadFile(ks.file)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ks.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status fetching %s: %d", ks.url, resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", jwk.Crv)
+		}
+
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", jwk.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(bytes), nil
+}
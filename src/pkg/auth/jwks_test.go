@@ -0,0 +1,106 @@
+package pkg_auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newJWKS(t *testing.T, kid string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	jwks, err := json.Marshal(jsonWebKeySet{Keys: []jsonWebKey{{
+		Kty: "EC",
+		Kid: kid,
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(key.X.Bytes()),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.Bytes()),
+	}}})
+	assert.NoError(t, err)
+
+	return jwks
+}
+
+func Test_KeySet_Key(t *testing.T) {
+	t.Run("ok - collapse concurrent refreshes", func(t *testing.T) {
+		jwks := newJWKS(t, "key-1")
+
+		var requests atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			time.Sleep(100 * time.Millisecond)
+			w.Write(jwks)
+		}))
+		defer srv.Close()
+
+		ks := NewKeySet(srv.URL, "", time.Hour)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				key, err := ks.Key(context.Background(), "key-1")
+				assert.NotNil(t, key)
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), requests.Load())
+	})
+	t.Run("nok - do not refresh again right after a failure", func(t *testing.T) {
+		var requests atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		ks := NewKeySet(srv.URL, "", time.Hour)
+
+		for i := 0; i < 3; i++ {
+			key, err := ks.Key(context.Background(), "key-1")
+			assert.Nil(t, key)
+			assert.Error(t, err)
+		}
+
+		assert.Equal(t, int32(1), requests.Load())
+	})
+	t.Run("ok - keep serving known keys when a refresh fails", func(t *testing.T) {
+		jwks := newJWKS(t, "key-1")
+
+		var failing atomic.Bool
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if failing.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write(jwks)
+		}))
+		defer srv.Close()
+
+		ks := NewKeySet(srv.URL, "", time.Hour)
+		assert.NoError(t, ks.Refresh(context.Background()))
+
+		failing.Store(true)
+		assert.Error(t, ks.Refresh(context.Background()))
+
+		key, err := ks.Key(context.Background(), "key-1")
+		assert.NotNil(t, key)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,87 @@
+package pkg_auth
+
+import (
+	"errors"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	pkg_errors "github.com/teyz/go-svc-template/pkg/errors"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+// Middleware authenticates requests with the first authenticator finding
+// credentials in them and stores the claims of the caller in the request context.
+func Middleware(authenticators ...Authenticator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+
+			for _, authenticator := range authenticators {
+				claims, err := authenticator.Authenticate(c)
+				if errors.Is(err, ErrNoCredentials) {
+					continue
+				}
+				if err != nil {
+					log.Warn().Err(err).
+						Msg("pkg_auth.Middleware: rejecting request")
+					if pkg_errors.IsExpiredResourceError(err) {
+						return c.JSON(pkg_http.TranslateError(ctx, pkg_errors.NewUnauthorizedError(pkg_http.MessageExpiredCredentialsError)))
+					}
+					return c.JSON(pkg_http.TranslateError(ctx, pkg_errors.NewUnauthorizedError(pkg_http.MessageUnauthorizedError)))
+				}
+
+				c.SetRequest(c.Request().WithContext(WithClaims(ctx, claims)))
+
+				return next(c)
+			}
+
+			return c.JSON(pkg_http.TranslateError(ctx, pkg_errors.NewUnauthorizedError(pkg_http.MessageUnauthorizedError)))
+		}
+	}
+}
+
+// RequireScopes rejects requests whose caller was not granted every scope.
+func RequireScopes(scopes ...string) echo.MiddlewareFunc {
+	return require(func(claims *Claims) bool {
+		for _, scope := range scopes {
+			if !claims.HasScope(scope) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// RequireRoles rejects requests whose caller has none of the roles.
+func RequireRoles(roles ...string) echo.MiddlewareFunc {
+	return require(func(claims *Claims) bool {
+		for _, role := range roles {
+			if claims.HasRole(role) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+func require(allowed func(claims *Claims) bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+
+			claims, ok := ClaimsFromContext(ctx)
+			if !ok {
+				return c.JSON(pkg_http.TranslateError(ctx, pkg_errors.NewUnauthorizedError(pkg_http.MessageUnauthorizedError)))
+			}
+
+			if !allowed(claims) {
+				return c.JSON(pkg_http.TranslateError(ctx, pkg_errors.NewForbiddenError(pkg_http.MessageForbidenError)))
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package pkg_auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveWithScopes(t *testing.T, headers map[string]string, scopes ...string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, Middleware(NewHeaderAuthenticator()), RequireScopes(scopes...))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func Test_RequireScopes(t *testing.T) {
+	t.Run("ok - caller has every scope", func(t *testing.T) {
+		rec := serveWithScopes(t, map[string]string{
+			HeaderSubject: "user-1",
+			HeaderScopes:  "examples:read,examples:write",
+		}, "examples:read", "examples:write")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("nok - caller is missing a scope", func(t *testing.T) {
+		rec := serveWithScopes(t, map[string]string{
+			HeaderSubject: "user-1",
+			HeaderScopes:  "examples:read",
+		}, "examples:read", "examples:write")
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("nok - caller is not authenticated", func(t *testing.T) {
+		rec := serveWithScopes(t, nil, "examples:read")
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func Test_RequireRoles(t *testing.T) {
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, Middleware(NewHeaderAuthenticator()), RequireRoles("admin", "operator"))
+
+	t.Run("ok - caller has one of the roles", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(HeaderSubject, "user-1")
+		req.Header.Set(HeaderRoles, "operator")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("nok - caller has none of the roles", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(HeaderSubject, "user-1")
+		req.Header.Set(HeaderRoles, "viewer")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
@@ -0,0 +1,12 @@
+package pkg_auth
+
+import "context"
+
+// Policy decides whether the caller of ctx may perform action on resource, which
+// may be nil for actions on collections. It returns a ForbiddenError otherwise.
+type Policy func(ctx context.Context, action string, resource interface{}) error
+
+// AllowAll is the policy allowing every action.
+func AllowAll(ctx context.Context, action string, resource interface{}) error {
+	return nil
+}
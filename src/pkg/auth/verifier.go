@@ -0,0 +1,69 @@
+package pkg_auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/teyz/go-svc-template/pkg/errors"
+)
+
+// Verifier checks the signature and the registered claims of bearer tokens.
+type Verifier struct {
+	secret  []byte
+	keySet  *KeySet
+	options []jwt.ParserOption
+}
+
+func NewVerifier(cfg *AuthConfig) *Verifier {
+	v := &Verifier{}
+
+	methods := make([]string, 0)
+	if cfg.JWTSecret != "" {
+		v.secret = []byte(cfg.JWTSecret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.JWKSURL != "" || cfg.JWKSFile != "" {
+		v.keySet = NewKeySet(cfg.JWKSURL, cfg.JWKSFile, cfg.JWKSRefreshInterval)
+		methods = append(methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg())
+	}
+
+	v.options = []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		v.options = append(v.options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		v.options = append(v.options, jwt.WithAudience(cfg.Audience))
+	}
+
+	return v
+}
+
+// Verify parses token and returns its claims, or an UnauthorizedError.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	claims := &Claims{}
+
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		switch t.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			return v.secret, nil
+		default:
+			if v.keySet == nil {
+				return nil, fmt.Errorf("no key set configured")
+			}
+
+			kid, _ := t.Header["kid"].(string)
+			return v.keySet.Key(ctx, kid)
+		}
+	}, v.options...)
+	if err != nil {
+		return nil, errors.NewUnauthorizedError(fmt.Sprintf("pkg_auth.Verifier.Verify: invalid token: %v", err.Error()))
+	}
+
+	return claims, nil
+}
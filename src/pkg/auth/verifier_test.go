@@ -0,0 +1,119 @@
+package pkg_auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/teyz/go-svc-template/pkg/errors"
+)
+
+func signHS256(t *testing.T, secret string, claims jwt.Claims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	assert.NoError(t, err)
+
+	return token
+}
+
+func Test_Verify(t *testing.T) {
+	cfg := &AuthConfig{
+		JWTSecret: "secret",
+		Issuer:    "https://issuer.example.com",
+		Audience:  "go-svc-template",
+	}
+
+	t.Run("ok - verify hs256 token", func(t *testing.T) {
+		token := signHS256(t, "secret", jwt.RegisteredClaims{
+			Subject:   "user",
+			Issuer:    "https://issuer.example.com",
+			Audience:  jwt.ClaimStrings{"go-svc-template"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		})
+
+		claims, err := NewVerifier(cfg).Verify(context.Background(), token)
+		assert.NoError(t, err)
+		assert.Equal(t, "user", claims.Subject)
+	})
+	t.Run("nok - verify expired token", func(t *testing.T) {
+		token := signHS256(t, "secret", jwt.RegisteredClaims{
+			Issuer:    "https://issuer.example.com",
+			Audience:  jwt.ClaimStrings{"go-svc-template"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+		})
+
+		claims, err := NewVerifier(cfg).Verify(context.Background(), token)
+		assert.Nil(t, claims)
+		assert.True(t, errors.IsUnauthorizedError(err))
+	})
+	t.Run("nok - verify token not yet valid", func(t *testing.T) {
+		token := signHS256(t, "secret", jwt.RegisteredClaims{
+			Issuer:    "https://issuer.example.com",
+			Audience:  jwt.ClaimStrings{"go-svc-template"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(2 * time.Hour)),
+			NotBefore: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		})
+
+		claims, err := NewVerifier(cfg).Verify(context.Background(), token)
+		assert.Nil(t, claims)
+		assert.True(t, errors.IsUnauthorizedError(err))
+	})
+	t.Run("nok - verify token from another issuer", func(t *testing.T) {
+		token := signHS256(t, "secret", jwt.RegisteredClaims{
+			Issuer:    "https://other.example.com",
+			Audience:  jwt.ClaimStrings{"go-svc-template"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		})
+
+		claims, err := NewVerifier(cfg).Verify(context.Background(), token)
+		assert.Nil(t, claims)
+		assert.True(t, errors.IsUnauthorizedError(err))
+	})
+	t.Run("nok - verify token with wrong secret", func(t *testing.T) {
+		token := signHS256(t, "other", jwt.RegisteredClaims{
+			Issuer:    "https://issuer.example.com",
+			Audience:  jwt.ClaimStrings{"go-svc-template"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		})
+
+		claims, err := NewVerifier(cfg).Verify(context.Background(), token)
+		assert.Nil(t, claims)
+		assert.True(t, errors.IsUnauthorizedError(err))
+	})
+	t.Run("ok - verify rs256 token from jwks file", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+
+		jwks, err := json.Marshal(jsonWebKeySet{Keys: []jsonWebKey{{
+			Kty: "RSA",
+			Kid: "key-1",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+		assert.NoError(t, err)
+
+		path := filepath.Join(t.TempDir(), "jwks.json")
+		assert.NoError(t, os.WriteFile(path, jwks, 0o600))
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+			Subject:   "user",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		})
+		token.Header["kid"] = "key-1"
+		signed, err := token.SignedString(key)
+		assert.NoError(t, err)
+
+		claims, err := NewVerifier(&AuthConfig{JWKSFile: path, JWKSRefreshInterval: time.Hour}).Verify(context.Background(), signed)
+		assert.NoError(t, err)
+		assert.Equal(t, "user", claims.Subject)
+	})
+}
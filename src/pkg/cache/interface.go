@@ -5,11 +5,24 @@ import (
 	"time"
 )
 
+// RateLimitResult is the outcome of a rate limit check.
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long to wait before the next request is allowed, when denied
+	RetryAfter time.Duration
+	// ResetAfter is how long until the quota is fully replenished
+	ResetAfter time.Duration
+}
+
 type Cache interface {
 	Set(ctx context.Context, key string, value interface{}) error
 	Get(ctx context.Context, key string) (string, error)
 	TTL(ctx context.Context, key string) (time.Duration, error)
 	SetEx(ctx context.Context, key string, value interface{}, duration time.Duration) error
+	SetExWithTags(ctx context.Context, key string, value interface{}, duration time.Duration, tags ...string) error
+	InvalidateTags(ctx context.Context, tags ...string) error
+	AllowRate(ctx context.Context, key string, rate int, period time.Duration, burst int) (*RateLimitResult, error)
 	Del(ctx context.Context, key string) error
 	DelAll(ctx context.Context, keys ...string) error
 	Incr(ctx context.Context, key string) error
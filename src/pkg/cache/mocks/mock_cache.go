@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 	time "time"
 
+	pkg_cache "github.com/teyz/go-svc-template/pkg/cache"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -40,6 +41,21 @@ func (m *MockCache) EXPECT() *MockCacheMockRecorder {
 	return m.recorder
 }
 
+// AllowRate mocks base method.
+func (m *MockCache) AllowRate(ctx context.Context, key string, rate int, period time.Duration, burst int) (*pkg_cache.RateLimitResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllowRate", ctx, key, rate, period, burst)
+	ret0, _ := ret[0].(*pkg_cache.RateLimitResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllowRate indicates an expected call of AllowRate.
+func (mr *MockCacheMockRecorder) AllowRate(ctx, key, rate, period, burst any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllowRate", reflect.TypeOf((*MockCache)(nil).AllowRate), ctx, key, rate, period, burst)
+}
+
 // Decr mocks base method.
 func (m *MockCache) Decr(ctx context.Context, key string) error {
 	m.ctrl.T.Helper()
@@ -202,6 +218,25 @@ func (mr *MockCacheMockRecorder) Incr(ctx, key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Incr", reflect.TypeOf((*MockCache)(nil).Incr), ctx, key)
 }
 
+// InvalidateTags mocks base method.
+func (m *MockCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range tags {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InvalidateTags", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateTags indicates an expected call of InvalidateTags.
+func (mr *MockCacheMockRecorder) InvalidateTags(ctx any, tags ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, tags...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateTags", reflect.TypeOf((*MockCache)(nil).InvalidateTags), varargs...)
+}
+
 // LLen mocks base method.
 func (m *MockCache) LLen(ctx context.Context, key string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -433,6 +468,25 @@ func (mr *MockCacheMockRecorder) SetEx(ctx, key, value, duration any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEx", reflect.TypeOf((*MockCache)(nil).SetEx), ctx, key, value, duration)
 }
 
+// SetExWithTags mocks base method.
+func (m *MockCache) SetExWithTags(ctx context.Context, key string, value any, duration time.Duration, tags ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key, value, duration}
+	for _, a := range tags {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetExWithTags", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetExWithTags indicates an expected call of SetExWithTags.
+func (mr *MockCacheMockRecorder) SetExWithTags(ctx, key, value, duration any, tags ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key, value, duration}, tags...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetExWithTags", reflect.TypeOf((*MockCache)(nil).SetExWithTags), varargs...)
+}
+
 // TTL mocks base method.
 func (m *MockCache) TTL(ctx context.Context, key string) (time.Duration, error) {
 	m.ctrl.T.Helper()
@@ -534,4 +588,4 @@ func (m *MockCache) ZRem(ctx context.Context, key string, value any) (int64, err
 func (mr *MockCacheMockRecorder) ZRem(ctx, key, value any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRem", reflect.TypeOf((*MockCache)(nil).ZRem), ctx, key, value)
-}
\ No newline at end of file
+}
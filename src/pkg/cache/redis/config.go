@@ -1,6 +1,6 @@
 package pkg_redis
 
 type RedisConfig struct {
-	CacheHost string `env:"CACHE_HOST"`
-	CachePort uint16 `env:"CACHE_PORT"`
+	CacheHost string `env:"CACHE_HOST,required,notEmpty"`
+	CachePort uint16 `env:"CACHE_PORT,required" validate:"min=1"`
 }
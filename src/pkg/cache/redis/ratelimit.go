@@ -0,0 +1,63 @@
+package pkg_redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+
+	pkg_cache "github.com/teyz/go-svc-template/pkg/cache"
+)
+
+// allowRateScript implements the generic cell rate algorithm: the key holds the
+// theoretical arrival time of the next request, in seconds read from the clock of
+// Redis so that every instance agrees on it.
+var allowRateScript = redis.NewScript(`
+redis.replicate_commands()
+
+local burst = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local period = tonumber(ARGV[3])
+
+local emission_interval = period / rate
+local burst_offset = emission_interval * burst
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) + tonumber(time[2]) / 1000000
+
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+tat = math.max(tat, now)
+
+local new_tat = tat + emission_interval
+local diff = now - (new_tat - burst_offset)
+if diff < 0 then
+	return {0, 0, tostring(-diff), tostring(tat - now)}
+end
+
+local reset_after = new_tat - now
+redis.call('SET', KEYS[1], tostring(new_tat), 'EX', math.ceil(reset_after))
+
+return {1, math.floor(diff / emission_interval), '0', tostring(reset_after)}
+`)
+
+func (c *cacheClient) AllowRate(ctx context.Context, key string, rate int, period time.Duration, burst int) (*pkg_cache.RateLimitResult, error) {
+	values, err := allowRateScript.Run(ctx, c.rdb, []string{key}, burst, rate, period.Seconds()).Slice()
+	if err != nil {
+		log.Error().Err(err).
+			Str("key", key).
+			Msg("unable to check rate limit in the cache")
+		return nil, err
+	}
+
+	retryAfter, _ := strconv.ParseFloat(values[2].(string), 64)
+	resetAfter, _ := strconv.ParseFloat(values[3].(string), 64)
+
+	return &pkg_cache.RateLimitResult{
+		Allowed:    values[0].(int64) == 1,
+		Remaining:  int(values[1].(int64)),
+		RetryAfter: time.Duration(retryAfter * float64(time.Second)),
+		ResetAfter: time.Duration(resetAfter * float64(time.Second)),
+	}, nil
+}
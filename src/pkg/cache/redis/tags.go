@@ -0,0 +1,70 @@
+package pkg_redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+func (c *cacheClient) SetExWithTags(ctx context.Context, key string, value interface{}, duration time.Duration, tags ...string) error {
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetEx(ctx, key, value, duration)
+		for _, tag := range tags {
+			pipe.SAdd(ctx, tag, key)
+			// the tag set must outlive the longest key it references
+			pipe.ExpireNX(ctx, tag, duration)
+			pipe.ExpireGT(ctx, tag, duration)
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Error().Err(err).
+			Str("key", key).
+			Strs("tags", tags).
+			Msg("unable to set tagged key in the cache")
+		return err
+	}
+
+	return nil
+}
+
+// InvalidateTags deletes every key referenced by the given tags. Keys are
+// deleted one by one so that they may live in different cluster slots, and are
+// removed from their tag rather than deleting it, so that a key tagged after
+// the tag was read stays referenced for the next invalidation.
+func (c *cacheClient) InvalidateTags(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		keys, err := c.SMembers(ctx, tag)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			continue
+		}
+
+		members := make([]interface{}, 0, len(keys))
+		for _, key := range keys {
+			members = append(members, key)
+		}
+
+		_, err = c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, key := range keys {
+				pipe.Del(ctx, key)
+			}
+			pipe.SRem(ctx, tag, members...)
+
+			return nil
+		})
+		if err != nil {
+			log.Error().Err(err).
+				Str("tag", tag).
+				Msg("unable to invalidate tag in the cache")
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,12 +1,173 @@
 package pkg_config
 
-import "github.com/caarlos0/env/v10"
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/caarlos0/env/v10"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// fileSuffix marks variables holding the path of a file to read the value from,
+	// e.g. DB_PASSWORD_FILE=/run/secrets/db_password supplies DB_PASSWORD.
+	fileSuffix = "_FILE"
+
+	// deprecatedEnvironmentKey is the misspelled name ENVIRONMENT used to be read from.
+	deprecatedEnvironmentKey = "ENVRIONMENT"
+	environmentKey           = "ENVIRONMENT"
+	defaultEnvironment       = "local"
+)
 
 type ServiceConfig struct {
-	ServiceName string `env:"SERVICE_NAME"`
-	Environment string `env:"ENVRIONMENT" envDefault:"local"`
+	ServiceName string `env:"SERVICE_NAME,required,notEmpty" reload:"false"`
+	Environment string `env:"ENVIRONMENT" envDefault:"local" reload:"false"`
+	LogLevel    string `env:"LOG_LEVEL" envDefault:"info" validate:"oneof=trace debug info warn error"`
+}
+
+// Validator is implemented by config structs needing checks that tags cannot express.
+type Validator interface {
+	Validate() error
 }
 
-func ParseConfig[T any](cfg *T) error {
-	return env.Parse(cfg)
+// ParseConfig fills cfg from the optional config file, its environment overlay
+// and the environment, each layer overriding the previous one, then validates
+// it. Every problem is reported at once in the returned error.
+func ParseConfig[T any](cfg *T, opts ...Option) error {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	environ, errs := environment(o, envNames(reflect.TypeOf(cfg).Elem(), make(map[string]bool)))
+
+	err := env.ParseWithOptions(cfg, env.Options{
+		Environment: environ,
+	})
+	if err != nil {
+		var aggregateErr env.AggregateError
+		if errors.As(err, &aggregateErr) {
+			errs = append(errs, aggregateErr.Errors...)
+		} else {
+			errs = append(errs, err)
+		}
+	}
+
+	errs = append(errs, validate(reflect.ValueOf(cfg).Elem(), environ)...)
+
+	return errors.Join(errs...)
+}
+
+// environment merges the config files with the process environment, resolving
+// the _FILE variables of names and mapping deprecated names to their
+// replacement.
+func environment(o *options, names map[string]bool) (map[string]string, []error) {
+	processEnviron := env.ToMap(os.Environ())
+	errs := resolveFiles(processEnviron, names)
+
+	environ := make(map[string]string)
+
+	path := o.configFile
+	if path == "" {
+		path = processEnviron[configFileKey]
+	}
+	if path != "" {
+		values, err := loadConfigFiles(path, processEnviron)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			errs = append(errs, resolveFiles(values, names)...)
+			environ = values
+		}
+	}
+
+	for key, value := range processEnviron {
+		environ[key] = value
+	}
+
+	if value, ok := environ[deprecatedEnvironmentKey]; ok {
+		log.Warn().
+			Msgf("pkg_config.ParseConfig: %s is deprecated, use %s instead", deprecatedEnvironmentKey, environmentKey)
+
+		if _, exists := environ[environmentKey]; !exists {
+			environ[environmentKey] = value
+		}
+	}
+
+	return environ, errs
+}
+
+// resolveFiles replaces every X_FILE variable of environ by X set to the file
+// content, X being one of names. Other variables ending with _FILE, such as
+// CONFIG_FILE or HTTP_TLS_KEY_FILE, are paths rather than secrets.
+func resolveFiles(environ map[string]string, names map[string]bool) []error {
+	errs := make([]error, 0)
+
+	files := make(map[string]string)
+	for key, path := range environ {
+		if name, ok := strings.CutSuffix(key, fileSuffix); ok && names[name] {
+			files[name] = path
+		}
+	}
+
+	for name, path := range files {
+		key := name + fileSuffix
+
+		if _, exists := environ[name]; exists {
+			errs = append(errs, fmt.Errorf("env: both %s and %s are set", name, key))
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("env: unable to read %s: %w", key, err))
+			continue
+		}
+
+		environ[name] = strings.TrimRight(string(content), "\r\n")
+	}
+
+	return errs
+}
+
+// envNames adds the variable names declared by the env tags of t and its nested
+// structs to names and returns it.
+func envNames(t reflect.Type, names map[string]bool) map[string]bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, ok := field.Tag.Lookup("env")
+		if !ok {
+			if field.Type.Kind() == reflect.Struct {
+				envNames(field.Type, names)
+			}
+			continue
+		}
+
+		name, _, _ = strings.Cut(name, ",")
+		names[name] = true
+	}
+
+	return names
+}
+
+// environmentName returns the deployment environment, the process environment
+// taking precedence over the config file values.
+func environmentName(environ, values map[string]string) string {
+	for _, layer := range []map[string]string{environ, values} {
+		if value, ok := layer[environmentKey]; ok {
+			return value
+		}
+		if value, ok := layer[deprecatedEnvironmentKey]; ok {
+			return value
+		}
+	}
+
+	return defaultEnvironment
 }
@@ -0,0 +1,188 @@
+package pkg_config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testConfig struct {
+	ServiceConfig ServiceConfig
+
+	Port     uint16        `env:"TEST_PORT,required" validate:"min=1"`
+	Password string        `env:"TEST_PASSWORD" secret:"true"`
+	CertFile string        `env:"TEST_CERT_FILE"`
+	Mode     string        `env:"TEST_MODE" envDefault:"fast" validate:"oneof=fast slow"`
+	Timeout  time.Duration `env:"TEST_TIMEOUT" envDefault:"1s" validate:"min=1s,max=1m"`
+}
+
+func Test_ParseConfig(t *testing.T) {
+	t.Run("ok - parse config", func(t *testing.T) {
+		t.Setenv("SERVICE_NAME", "go-svc-template")
+		t.Setenv("TEST_PORT", "3003")
+
+		cfg := &testConfig{}
+		err := ParseConfig(cfg)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "go-svc-template", cfg.ServiceConfig.ServiceName)
+		assert.Equal(t, "local", cfg.ServiceConfig.Environment)
+		assert.Equal(t, uint16(3003), cfg.Port)
+		assert.Equal(t, "fast", cfg.Mode)
+		assert.Equal(t, time.Second, cfg.Timeout)
+	})
+	t.Run("ok - parse config with secret from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "password")
+		assert.NoError(t, os.WriteFile(path, []byte("hello world !\n"), 0o600))
+
+		t.Setenv("SERVICE_NAME", "go-svc-template")
+		t.Setenv("TEST_PORT", "3003")
+		t.Setenv("TEST_PASSWORD_FILE", path)
+
+		cfg := &testConfig{}
+		err := ParseConfig(cfg)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "hello world !", cfg.Password)
+	})
+	t.Run("ok - parse config with paths ending with _FILE", func(t *testing.T) {
+		t.Setenv("SERVICE_NAME", "go-svc-template")
+		t.Setenv("TEST_PORT", "3003")
+		t.Setenv("TEST_CERT_FILE", "/does/not/exist")
+		t.Setenv("UNKNOWN_FILE", "/does/not/exist")
+
+		cfg := &testConfig{}
+		err := ParseConfig(cfg)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "/does/not/exist", cfg.CertFile)
+	})
+	t.Run("ok - parse config with deprecated environment", func(t *testing.T) {
+		t.Setenv("SERVICE_NAME", "go-svc-template")
+		t.Setenv("TEST_PORT", "3003")
+		t.Setenv("ENVRIONMENT", "production")
+
+		cfg := &testConfig{}
+		err := ParseConfig(cfg)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "production", cfg.ServiceConfig.Environment)
+	})
+	t.Run("ok - parse config from files and environment", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("service_name: go-svc-template\ntest:\n  port: 3003\n  mode: slow\n"), 0o600))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "config.prod.yaml"), []byte("test:\n  timeout: 30s\n"), 0o600))
+
+		t.Setenv("ENVIRONMENT", "prod")
+		t.Setenv("TEST_MODE", "fast")
+
+		cfg := &testConfig{}
+		err := ParseConfig(cfg, WithConfigFile(filepath.Join(dir, "config.yaml")))
+		assert.NoError(t, err)
+
+		assert.Equal(t, "go-svc-template", cfg.ServiceConfig.ServiceName)
+		assert.Equal(t, uint16(3003), cfg.Port)
+		assert.Equal(t, "fast", cfg.Mode)
+		assert.Equal(t, 30*time.Second, cfg.Timeout)
+	})
+	t.Run("ok - parse config from toml file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		assert.NoError(t, os.WriteFile(path, []byte("SERVICE_NAME = \"go-svc-template\"\n\n[test]\nport = 3003\n"), 0o600))
+
+		t.Setenv("CONFIG_FILE", path)
+
+		cfg := &testConfig{}
+		err := ParseConfig(cfg)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "go-svc-template", cfg.ServiceConfig.ServiceName)
+		assert.Equal(t, uint16(3003), cfg.Port)
+	})
+	t.Run("nok - parse config with every error reported", func(t *testing.T) {
+		t.Setenv("TEST_PORT", "0")
+		t.Setenv("TEST_MODE", "medium")
+		t.Setenv("TEST_TIMEOUT", "1h")
+
+		cfg := &testConfig{}
+		err := ParseConfig(cfg)
+		assert.Error(t, err)
+
+		assert.ErrorContains(t, err, `"SERVICE_NAME" is not set`)
+		assert.ErrorContains(t, err, "TEST_PORT must be at least 1")
+		assert.ErrorContains(t, err, "TEST_MODE must be one of fast, slow")
+		assert.ErrorContains(t, err, "TEST_TIMEOUT must be at most 1m")
+	})
+	t.Run("nok - parse config with both value and file", func(t *testing.T) {
+		t.Setenv("SERVICE_NAME", "go-svc-template")
+		t.Setenv("TEST_PORT", "3003")
+		t.Setenv("TEST_PASSWORD", "hello world !")
+		t.Setenv("TEST_PASSWORD_FILE", "/does/not/exist")
+
+		cfg := &testConfig{}
+		err := ParseConfig(cfg)
+		assert.ErrorContains(t, err, "both TEST_PASSWORD and TEST_PASSWORD_FILE are set")
+	})
+}
+
+func Test_Redacted(t *testing.T) {
+	t.Run("ok - redact secrets", func(t *testing.T) {
+		cfg := &testConfig{
+			Port:     3003,
+			Password: "hello world !",
+		}
+
+		entries := Redacted(cfg)
+		assert.Contains(t, entries, "TEST_PORT=3003")
+		assert.Contains(t, entries, "TEST_PASSWORD=******")
+		assert.NotContains(t, entries, "TEST_PASSWORD=hello world !")
+	})
+}
+
+func Test_Watcher(t *testing.T) {
+	t.Run("ok - reload config", func(t *testing.T) {
+		t.Setenv("SERVICE_NAME", "go-svc-template")
+		t.Setenv("TEST_PORT", "3003")
+
+		cfg := &testConfig{}
+		assert.NoError(t, ParseConfig(cfg))
+
+		w := NewWatcher(cfg)
+
+		var notified *testConfig
+		w.Subscribe(func(old, new *testConfig) {
+			notified = new
+		})
+
+		t.Setenv("SERVICE_NAME", "renamed")
+		t.Setenv("TEST_MODE", "slow")
+
+		err := w.Reload()
+		assert.NoError(t, err)
+
+		assert.Equal(t, "slow", w.Current().Mode)
+		assert.Equal(t, "go-svc-template", w.Current().ServiceConfig.ServiceName)
+		assert.Equal(t, w.Current(), notified)
+	})
+	t.Run("nok - reload invalid config", func(t *testing.T) {
+		t.Setenv("SERVICE_NAME", "go-svc-template")
+		t.Setenv("TEST_PORT", "3003")
+
+		cfg := &testConfig{}
+		assert.NoError(t, ParseConfig(cfg))
+
+		w := NewWatcher(cfg)
+		w.Subscribe(func(old, new *testConfig) {
+			t.Error("subscriber must not be notified")
+		})
+
+		t.Setenv("TEST_MODE", "medium")
+
+		err := w.Reload()
+		assert.Error(t, err)
+
+		assert.Equal(t, cfg, w.Current())
+	})
+}
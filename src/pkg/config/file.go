@@ -0,0 +1,105 @@
+package pkg_config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// configFileKey is the variable holding the config file path when none is given.
+const configFileKey = "CONFIG_FILE"
+
+type options struct {
+	configFile string
+}
+
+type Option func(*options)
+
+// WithConfigFile reads the config file at path, it takes precedence over CONFIG_FILE.
+func WithConfigFile(path string) Option {
+	return func(o *options) {
+		o.configFile = path
+	}
+}
+
+// loadConfigFiles reads the config file at path and its overlay for the given
+// environment, e.g. config.yaml then config.prod.yaml. Keys are variable names,
+// nested keys being joined with underscores: db.host supplies DB_HOST.
+func loadConfigFiles(path string, environ map[string]string) (map[string]string, error) {
+	values, err := loadConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	environment := environmentName(environ, values)
+	ext := filepath.Ext(path)
+	overlayPath := strings.TrimSuffix(path, ext) + "." + environment + ext
+
+	overlay, err := loadConfigFile(overlayPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return values, nil
+		}
+		return nil, err
+	}
+
+	for key, value := range overlay {
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+func loadConfigFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make(map[string]interface{})
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &raw)
+	case ".toml":
+		err = toml.Unmarshal(content, &raw)
+	default:
+		return nil, fmt.Errorf("config: unsupported config file extension %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: unable to decode %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	flatten("", raw, values)
+
+	return values, nil
+}
+
+func flatten(prefix string, value interface{}, out map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			name := strings.ToUpper(key)
+			if prefix != "" {
+				name = prefix + "_" + name
+			}
+			flatten(name, nested, out)
+		}
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		out[prefix] = strings.Join(items, ",")
+	case nil:
+		out[prefix] = ""
+	default:
+		out[prefix] = fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,56 @@
+package pkg_config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const redactedValue = "******"
+
+// Redacted lists every env-tagged field of cfg as NAME=value, in declaration
+// order. Fields tagged `secret:"true"` are redacted when set.
+func Redacted[T any](cfg *T) []string {
+	return redactedEntries(reflect.ValueOf(cfg).Elem())
+}
+
+func redactedEntries(v reflect.Value) []string {
+	entries := make([]string, 0)
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, ok := field.Tag.Lookup("env")
+		if !ok {
+			if v.Field(i).Kind() == reflect.Struct {
+				entries = append(entries, redactedEntries(v.Field(i))...)
+			}
+			continue
+		}
+
+		name, _, _ = strings.Cut(name, ",")
+		entries = append(entries, fmt.Sprintf("%s=%s", name, formatValue(v.Field(i), field.Tag.Get("secret") == "true")))
+	}
+
+	return entries
+}
+
+func formatValue(v reflect.Value, secret bool) string {
+	if secret && !v.IsZero() {
+		return redactedValue
+	}
+
+	if v.Kind() == reflect.Slice {
+		values := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, fmt.Sprint(v.Index(i).Interface()))
+		}
+
+		return strings.Join(values, ",")
+	}
+
+	return fmt.Sprint(v.Interface())
+}
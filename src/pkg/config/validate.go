@@ -0,0 +1,125 @@
+package pkg_config
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// validate checks the `validate` tags of v and calls Validate on every struct
+// implementing Validator. Supported rules are min=, max= and oneof=. Rules are
+// skipped for unset variables without default, those are reported by required.
+func validate(v reflect.Value, environ map[string]string) []error {
+	errs := make([]error, 0)
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if v.Field(i).Kind() == reflect.Struct {
+			errs = append(errs, validate(v.Field(i), environ)...)
+			continue
+		}
+
+		rules, ok := field.Tag.Lookup("validate")
+		if !ok {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("env"), ",")
+		if _, set := environ[name]; !set {
+			if _, hasDefault := field.Tag.Lookup("envDefault"); !hasDefault {
+				continue
+			}
+		}
+
+		for _, rule := range strings.Split(rules, ",") {
+			if err := checkRule(v.Field(i), name, rule); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if validator, ok := v.Addr().Interface().(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+func checkRule(v reflect.Value, name, rule string) error {
+	op, arg, _ := strings.Cut(rule, "=")
+
+	switch op {
+	case "min", "max":
+		cmp, err := compare(v, arg)
+		if err != nil {
+			return fmt.Errorf("env: invalid rule %q on %s: %w", rule, name, err)
+		}
+		if op == "min" && cmp < 0 {
+			return fmt.Errorf("env: %s must be at least %s", name, arg)
+		}
+		if op == "max" && cmp > 0 {
+			return fmt.Errorf("env: %s must be at most %s", name, arg)
+		}
+	case "oneof":
+		allowed := strings.Fields(arg)
+		value := fmt.Sprint(v.Interface())
+		for _, a := range allowed {
+			if value == a {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("env: %s must be one of %s, got %q", name, strings.Join(allowed, ", "), value)
+	default:
+		return fmt.Errorf("env: unknown rule %q on %s", rule, name)
+	}
+
+	return nil
+}
+
+// compare returns -1, 0 or +1 as the value of v is lower, equal or greater than arg.
+func compare(v reflect.Value, arg string) (int, error) {
+	switch {
+	case v.Type() == durationType:
+		limit, err := time.ParseDuration(arg)
+		if err != nil {
+			return 0, err
+		}
+
+		return cmp.Compare(time.Duration(v.Int()), limit), nil
+	case v.CanInt():
+		limit, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		return cmp.Compare(v.Int(), limit), nil
+	case v.CanUint():
+		limit, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		return cmp.Compare(v.Uint(), limit), nil
+	case v.CanFloat():
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		return cmp.Compare(v.Float(), limit), nil
+	default:
+		return 0, fmt.Errorf("unsupported type %s", v.Type())
+	}
+}
@@ -0,0 +1,163 @@
+package pkg_config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+)
+
+// Watcher holds the current configuration and reloads it when the config file
+// changes or on SIGHUP. Fields tagged `reload:"false"` cannot change at runtime,
+// changes to them are ignored with a warning.
+type Watcher[T any] struct {
+	current     atomic.Pointer[T]
+	opts        []Option
+	path        string
+	mu          sync.Mutex
+	subscribers []func(old, new *T)
+}
+
+// NewWatcher returns a watcher starting from cfg, which must have been parsed
+// with the same options.
+func NewWatcher[T any](cfg *T, opts ...Option) *Watcher[T] {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	path := o.configFile
+	if path == "" {
+		path = os.Getenv(configFileKey)
+	}
+
+	w := &Watcher[T]{
+		opts: opts,
+		path: path,
+	}
+	w.current.Store(cfg)
+
+	return w
+}
+
+// Current returns the configuration in use. It must not be modified.
+func (w *Watcher[T]) Current() *T {
+	return w.current.Load()
+}
+
+// Subscribe registers fn to be called with the previous and the new configuration
+// after every successful reload.
+func (w *Watcher[T]) Subscribe(fn func(old, new *T)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Reload parses and validates the configuration again, then swaps it and notifies
+// subscribers. The current configuration is kept when the new one is invalid.
+func (w *Watcher[T]) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	next := new(T)
+	if err := ParseConfig(next, w.opts...); err != nil {
+		log.Error().Err(err).
+			Msg("pkg_config.Watcher.Reload: invalid configuration, keeping the current one")
+		return err
+	}
+
+	old := w.current.Load()
+	keepImmutable(reflect.ValueOf(old).Elem(), reflect.ValueOf(next).Elem(), "")
+
+	if reflect.DeepEqual(old, next) {
+		return nil
+	}
+
+	w.current.Store(next)
+
+	log.Info().
+		Msg("pkg_config.Watcher.Reload: configuration reloaded")
+
+	for _, fn := range w.subscribers {
+		fn(old, next)
+	}
+
+	return nil
+}
+
+// Watch reloads the configuration on SIGHUP and on changes of the config file
+// or its overlays, until ctx is done.
+func (w *Watcher[T]) Watch(ctx context.Context) error {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var events chan fsnotify.Event
+	var errs chan error
+	if w.path != "" {
+		fsWatcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return err
+		}
+		defer fsWatcher.Close()
+
+		// the directory is watched as editors and Kubernetes replace files rather than write them
+		if err := fsWatcher.Add(filepath.Dir(w.path)); err != nil {
+			return err
+		}
+
+		events, errs = fsWatcher.Events, fsWatcher.Errors
+	}
+
+	prefix := strings.TrimSuffix(filepath.Base(w.path), filepath.Ext(w.path))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-hup:
+			w.Reload()
+		case event := <-events:
+			if strings.HasPrefix(filepath.Base(event.Name), prefix) {
+				w.Reload()
+			}
+		case err := <-errs:
+			log.Error().Err(err).
+				Msg("pkg_config.Watcher.Watch: unable to watch config file")
+		}
+	}
+}
+
+// keepImmutable copies back in next the fields of old tagged `reload:"false"`.
+func keepImmutable(old, next reflect.Value, prefix string) {
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := prefix + field.Name
+		if field.Tag.Get("reload") == "false" {
+			if !reflect.DeepEqual(old.Field(i).Interface(), next.Field(i).Interface()) {
+				log.Warn().
+					Str("field", name).
+					Msg("pkg_config.Watcher.Reload: field cannot change at runtime, restart to apply it")
+				next.Field(i).Set(old.Field(i))
+			}
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			keepImmutable(old.Field(i), next.Field(i), name+".")
+		}
+	}
+}
@@ -9,7 +9,10 @@ import (
 type DataPrefix string
 
 const (
-	Example DataPrefix = "exmp_"
+	Example    DataPrefix = "exmp_"
+	APIKey     DataPrefix = "akey_"
+	AuditEntry DataPrefix = "audt_"
+	Import     DataPrefix = "impt_"
 )
 
 func (dp DataPrefix) String() string {
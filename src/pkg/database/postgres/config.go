@@ -1,10 +1,65 @@
 package pkg_postgres
 
+import (
+	"errors"
+	"time"
+)
+
 type PostgresConfig struct {
+	// URL is a full connection string overriding the connection fields below,
+	// the optional settings being added to it unless it already sets them
+	URL      string `env:"DATABASE_URL" secret:"true"`
 	Host     string `env:"DB_HOST"`
 	Username string `env:"DB_USER"`
-	Password string `env:"DB_PASSWORD"`
+	Password string `env:"DB_PASSWORD" secret:"true"`
 	DBName   string `env:"DB_NAME"`
 	Port     uint16 `env:"DB_PORT"`
-	SSLMode  string `env:"DB_SSLMODE"`
+	SSLMode  string `env:"DB_SSLMODE" envDefault:"disable" validate:"oneof=disable allow prefer require verify-ca verify-full"`
+
+	SSLRootCert      string        `env:"DB_SSLROOTCERT"`
+	SSLCert          string        `env:"DB_SSLCERT"`
+	SSLKey           string        `env:"DB_SSLKEY"`
+	ApplicationName  string        `env:"DB_APPLICATION_NAME"`
+	StatementTimeout time.Duration `env:"DB_STATEMENT_TIMEOUT" validate:"min=0s"`
+
+	MaxOpenConns    int           `env:"DB_MAX_OPEN_CONNS" envDefault:"25" validate:"min=0"`
+	MaxIdleConns    int           `env:"DB_MAX_IDLE_CONNS" envDefault:"25" validate:"min=0"`
+	ConnMaxLifetime time.Duration `env:"DB_CONN_MAX_LIFETIME" envDefault:"30m" validate:"min=0s"`
+	ConnMaxIdleTime time.Duration `env:"DB_CONN_MAX_IDLE_TIME" envDefault:"5m" validate:"min=0s"`
+
+	ConnectTimeout      time.Duration `env:"DB_CONNECT_TIMEOUT" envDefault:"5s" validate:"min=0s"`
+	ConnectRetries      int           `env:"DB_CONNECT_RETRIES" envDefault:"5" validate:"min=0"`
+	ConnectRetryBackoff time.Duration `env:"DB_CONNECT_RETRY_BACKOFF" envDefault:"1s" validate:"min=0s"`
+
+	// AutoMigrate applies pending migrations at startup
+	AutoMigrate bool `env:"AUTO_MIGRATE" envDefault:"false"`
+
+	ReplicaDSNs                []string      `env:"DB_REPLICA_DSNS" envSeparator:"," secret:"true"`
+	ReplicaSelection           string        `env:"DB_REPLICA_SELECTION" envDefault:"round-robin" validate:"oneof=round-robin least-latency"`
+	ReplicaHealthCheckInterval time.Duration `env:"DB_REPLICA_HEALTH_CHECK_INTERVAL" envDefault:"5s" validate:"min=1s"`
+	// ReplicaMaxLag is how long replicas may take to apply a write
+	ReplicaMaxLag time.Duration `env:"DB_REPLICA_MAX_LAG" envDefault:"1s" validate:"min=0s"`
+}
+
+// Validate checks the connection fields are set when DATABASE_URL is not.
+func (cfg *PostgresConfig) Validate() error {
+	if cfg.URL != "" {
+		return nil
+	}
+
+	errs := make([]error, 0)
+	if cfg.Host == "" {
+		errs = append(errs, errors.New("env: DB_HOST is required when DATABASE_URL is not set"))
+	}
+	if cfg.Username == "" {
+		errs = append(errs, errors.New("env: DB_USER is required when DATABASE_URL is not set"))
+	}
+	if cfg.DBName == "" {
+		errs = append(errs, errors.New("env: DB_NAME is required when DATABASE_URL is not set"))
+	}
+	if cfg.Port == 0 {
+		errs = append(errs, errors.New("env: DB_PORT is required when DATABASE_URL is not set"))
+	}
+
+	return errors.Join(errs...)
 }
@@ -3,28 +3,229 @@ package pkg_postgres
 import (
 	"context"
 	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
 )
 
+// maxConnectRetryBackoff caps the delay between two connection attempts.
+const maxConnectRetryBackoff = 30 * time.Second
+
 func NewDatabaseConnection(ctx context.Context, cfg *PostgresConfig) (*sqlx.DB, error) {
-	// define disable as default ssl mode
-	if cfg.SSLMode == "" {
-		cfg.SSLMode = "disable"
+	db, err := sqlx.Open("postgres", cfg.DSN())
+	if err != nil {
+		return nil, err
 	}
 
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host,
-		cfg.Port,
-		cfg.Username,
-		cfg.Password,
-		cfg.DBName,
-		cfg.SSLMode)
+	ConfigurePool(db, cfg)
 
-	db, err := sqlx.Connect("postgres", dsn)
-	if err != nil {
+	if err := pingWithRetry(ctx, db, cfg); err != nil {
+		db.Close()
 		return nil, err
 	}
 
 	return db, nil
 }
+
+// DSN builds the connection string from the config. DATABASE_URL takes
+// precedence over the connection fields, the options of the config being added
+// to it unless it already sets them.
+func (cfg *PostgresConfig) DSN() string {
+	if cfg.URL != "" {
+		return cfg.withOptions(cfg.URL)
+	}
+
+	// define disable as default ssl mode
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	params := []string{
+		"host=" + quoteDSNValue(cfg.Host),
+		fmt.Sprintf("port=%d", cfg.Port),
+		"user=" + quoteDSNValue(cfg.Username),
+		"password=" + quoteDSNValue(cfg.Password),
+		"dbname=" + quoteDSNValue(cfg.DBName),
+		"sslmode=" + quoteDSNValue(sslMode),
+	}
+
+	for _, option := range cfg.options() {
+		params = append(params, option.key+"="+quoteDSNValue(option.value))
+	}
+
+	return strings.Join(params, " ")
+}
+
+type dsnOption struct {
+	key   string
+	value string
+}
+
+// options returns the optional connection settings of the config, in the order
+// they are written.
+func (cfg *PostgresConfig) options() []dsnOption {
+	options := make([]dsnOption, 0)
+
+	if cfg.SSLRootCert != "" {
+		options = append(options, dsnOption{"sslrootcert", cfg.SSLRootCert})
+	}
+	if cfg.SSLCert != "" {
+		options = append(options, dsnOption{"sslcert", cfg.SSLCert})
+	}
+	if cfg.SSLKey != "" {
+		options = append(options, dsnOption{"sslkey", cfg.SSLKey})
+	}
+	if cfg.ApplicationName != "" {
+		options = append(options, dsnOption{"application_name", cfg.ApplicationName})
+	}
+	if cfg.StatementTimeout > 0 {
+		// unknown keys are sent by lib/pq as run-time parameters of every connection
+		options = append(options, dsnOption{"statement_timeout", fmt.Sprintf("%d", cfg.StatementTimeout.Milliseconds())})
+	}
+	if cfg.ConnectTimeout > 0 {
+		// connect_timeout is in seconds, rounded up so that it is never 0, which disables it
+		options = append(options, dsnOption{"connect_timeout", fmt.Sprintf("%d", int(math.Ceil(cfg.ConnectTimeout.Seconds())))})
+	}
+
+	return options
+}
+
+// withOptions adds the options of the config missing from dsn, either a URL or
+// key/value pairs. The SSL mode is left to dsn, as it always has one.
+func (cfg *PostgresConfig) withOptions(dsn string) string {
+	options := cfg.options()
+	if len(options) == 0 {
+		return dsn
+	}
+
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			// left as is for lib/pq to report it
+			return dsn
+		}
+
+		query := u.Query()
+		for _, option := range options {
+			if !query.Has(option.key) {
+				query.Set(option.key, option.value)
+			}
+		}
+		u.RawQuery = query.Encode()
+
+		return u.String()
+	}
+
+	keys := dsnKeys(dsn)
+	params := []string{dsn}
+	for _, option := range options {
+		if !keys[option.key] {
+			params = append(params, option.key+"="+quoteDSNValue(option.value))
+		}
+	}
+
+	return strings.Join(params, " ")
+}
+
+// dsnKeys returns the keys set by a connection string of key/value pairs.
+func dsnKeys(dsn string) map[string]bool {
+	keys := make(map[string]bool)
+
+	for i := 0; i < len(dsn); {
+		// key, up to the equal sign
+		for i < len(dsn) && dsn[i] == ' ' {
+			i++
+		}
+		start := i
+		for i < len(dsn) && dsn[i] != '=' && dsn[i] != ' ' {
+			i++
+		}
+		if key := dsn[start:i]; key != "" {
+			keys[key] = true
+		}
+		for i < len(dsn) && (dsn[i] == ' ' || dsn[i] == '=') {
+			i++
+		}
+
+		// value, quoted values possibly holding escaped quotes and spaces
+		if i < len(dsn) && dsn[i] == '\'' {
+			for i++; i < len(dsn) && dsn[i] != '\''; i++ {
+				if dsn[i] == '\\' {
+					i++
+				}
+			}
+			i++
+			continue
+		}
+		for i < len(dsn) && dsn[i] != ' ' {
+			i++
+		}
+	}
+
+	return keys
+}
+
+// ConfigurePool applies the pool limits of the config to db.
+func ConfigurePool(db *sqlx.DB, cfg *PostgresConfig) {
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+}
+
+func pingWithRetry(ctx context.Context, db *sqlx.DB, cfg *PostgresConfig) error {
+	backoff := cfg.ConnectRetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		pingCtx := ctx
+		cancel := context.CancelFunc(func() {})
+		if cfg.ConnectTimeout > 0 {
+			pingCtx, cancel = context.WithTimeout(ctx, cfg.ConnectTimeout)
+		}
+		err := db.PingContext(pingCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= cfg.ConnectRetries {
+			return err
+		}
+
+		log.Warn().Err(err).
+			Int("attempt", attempt+1).
+			Dur("backoff", backoff).
+			Msg("pkg_postgres.NewDatabaseConnection: unable to reach database, retrying")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff = nextBackoff(backoff)
+	}
+}
+
+// nextBackoff doubles backoff up to maxConnectRetryBackoff.
+func nextBackoff(backoff time.Duration) time.Duration {
+	if backoff >= maxConnectRetryBackoff/2 {
+		return maxConnectRetryBackoff
+	}
+
+	return backoff * 2
+}
+
+// quoteDSNValue quotes a key/value connection string value as expected by lib/pq.
+func quoteDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
@@ -0,0 +1,218 @@
+package pkg_postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DSN(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  PostgresConfig
+		want string
+	}{
+		{
+			name: "ok - connection fields",
+			cfg:  PostgresConfig{Host: "localhost", Port: 5432, Username: "postgres", Password: "secret", DBName: "examples", SSLMode: "require"},
+			want: "host=localhost port=5432 user=postgres password=secret dbname=examples sslmode=require",
+		},
+		{
+			name: "ok - default ssl mode",
+			cfg:  PostgresConfig{Host: "localhost", Port: 5432, Username: "postgres", Password: "secret", DBName: "examples"},
+			want: "host=localhost port=5432 user=postgres password=secret dbname=examples sslmode=disable",
+		},
+		{
+			name: "ok - empty password",
+			cfg:  PostgresConfig{Host: "localhost", Port: 5432, Username: "postgres", DBName: "examples", SSLMode: "disable"},
+			want: "host=localhost port=5432 user=postgres password='' dbname=examples sslmode=disable",
+		},
+		{
+			name: "ok - optional fields",
+			cfg: PostgresConfig{
+				Host: "localhost", Port: 5432, Username: "postgres", Password: "secret", DBName: "examples", SSLMode: "verify-full",
+				SSLRootCert: "/certs/ca.crt", SSLCert: "/certs/client.crt", SSLKey: "/certs/client.key",
+				ApplicationName: "go svc", StatementTimeout: 30 * time.Second, ConnectTimeout: 5 * time.Second,
+			},
+			want: "host=localhost port=5432 user=postgres password=secret dbname=examples sslmode=verify-full " +
+				"sslrootcert=/certs/ca.crt sslcert=/certs/client.crt sslkey=/certs/client.key application_name='go svc' " +
+				"statement_timeout=30000 connect_timeout=5",
+		},
+		{
+			name: "ok - connect timeout rounded up",
+			cfg:  PostgresConfig{Host: "localhost", Port: 5432, Username: "postgres", Password: "secret", DBName: "examples", SSLMode: "disable", ConnectTimeout: 500 * time.Millisecond},
+			want: "host=localhost port=5432 user=postgres password=secret dbname=examples sslmode=disable connect_timeout=1",
+		},
+		{
+			name: "ok - DATABASE_URL overrides connection fields",
+			cfg:  PostgresConfig{URL: "postgres://postgres:secret@db:5432/examples", Host: "localhost", Port: 5433},
+			want: "postgres://postgres:secret@db:5432/examples",
+		},
+		{
+			name: "ok - DATABASE_URL with the options of the config",
+			cfg: PostgresConfig{
+				URL:             "postgres://postgres:secret@db:5432/examples?sslmode=require",
+				ApplicationName: "go svc", StatementTimeout: 30 * time.Second, ConnectTimeout: 5 * time.Second,
+			},
+			want: "postgres://postgres:secret@db:5432/examples?application_name=go+svc&connect_timeout=5&sslmode=require&statement_timeout=30000",
+		},
+		{
+			name: "ok - DATABASE_URL options take precedence",
+			cfg: PostgresConfig{
+				URL:             "postgresql://db/examples?application_name=worker&connect_timeout=10",
+				ApplicationName: "go-svc", ConnectTimeout: 5 * time.Second, SSLRootCert: "/certs/ca.crt",
+			},
+			want: "postgresql://db/examples?application_name=worker&connect_timeout=10&sslrootcert=%2Fcerts%2Fca.crt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+
+			assert.Equal(t, tt.want, cfg.DSN())
+			assert.Equal(t, tt.cfg, cfg)
+		})
+	}
+}
+
+func Test_PostgresConfig_withOptions(t *testing.T) {
+	cfg := PostgresConfig{ApplicationName: "go-svc", StatementTimeout: 30 * time.Second, ConnectTimeout: 5 * time.Second}
+
+	tests := []struct {
+		name string
+		dsn  string
+		want string
+	}{
+		{
+			name: "ok - key/value pairs",
+			dsn:  "host=replica dbname=examples",
+			want: "host=replica dbname=examples application_name=go-svc statement_timeout=30000 connect_timeout=5",
+		},
+		{
+			name: "ok - key/value pairs setting some options",
+			dsn:  `host=replica password='it\'s a connect_timeout=1' application_name = worker`,
+			want: `host=replica password='it\'s a connect_timeout=1' application_name = worker statement_timeout=30000 connect_timeout=5`,
+		},
+		{
+			name: "ok - URL",
+			dsn:  "postgres://replica/examples?statement_timeout=1000",
+			want: "postgres://replica/examples?application_name=go-svc&connect_timeout=5&statement_timeout=1000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cfg.withOptions(tt.dsn))
+		})
+	}
+}
+
+func Test_quoteDSNValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "ok - plain", value: "postgres", want: "postgres"},
+		{name: "ok - empty", value: "", want: "''"},
+		{name: "ok - spaces", value: "my password", want: "'my password'"},
+		{name: "ok - quotes", value: "it's", want: `'it\'s'`},
+		{name: "ok - backslashes", value: `a\b`, want: `'a\\b'`},
+		{name: "ok - quotes and backslashes", value: `\'`, want: `'\\\''`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, quoteDSNValue(tt.value))
+		})
+	}
+}
+
+func Test_PostgresConfig_Validate(t *testing.T) {
+	t.Run("ok - DATABASE_URL without connection fields", func(t *testing.T) {
+		cfg := PostgresConfig{URL: "postgres://postgres:secret@db:5432/examples"}
+
+		assert.NoError(t, cfg.Validate())
+	})
+	t.Run("nok - missing connection fields", func(t *testing.T) {
+		cfg := PostgresConfig{Host: "localhost"}
+
+		assert.Error(t, cfg.Validate())
+	})
+}
+
+func Test_pingWithRetry(t *testing.T) {
+	t.Run("ok - reachable after retries", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+		mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+		mock.ExpectPing()
+
+		err = pingWithRetry(context.Background(), sqlx.NewDb(db, "sqlmock"), &PostgresConfig{
+			ConnectRetries:      2,
+			ConnectRetryBackoff: time.Millisecond,
+		})
+		assert.NoError(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - retries exhausted", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+		mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+		err = pingWithRetry(context.Background(), sqlx.NewDb(db, "sqlmock"), &PostgresConfig{
+			ConnectRetries:      1,
+			ConnectRetryBackoff: time.Millisecond,
+		})
+		assert.Error(t, err)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("nok - ping timeout", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectPing().WillDelayFor(time.Hour)
+
+		err = pingWithRetry(context.Background(), sqlx.NewDb(db, "sqlmock"), &PostgresConfig{
+			ConnectTimeout: 10 * time.Millisecond,
+		})
+		assert.Error(t, err)
+	})
+	t.Run("nok - context done while waiting", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err = pingWithRetry(ctx, sqlx.NewDb(db, "sqlmock"), &PostgresConfig{
+			ConnectRetries:      5,
+			ConnectRetryBackoff: time.Hour,
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func Test_nextBackoff(t *testing.T) {
+	assert.Equal(t, 2*time.Second, nextBackoff(time.Second))
+	assert.Equal(t, maxConnectRetryBackoff, nextBackoff(maxConnectRetryBackoff/2))
+	assert.Equal(t, maxConnectRetryBackoff, nextBackoff(maxConnectRetryBackoff))
+}
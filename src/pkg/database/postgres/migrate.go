@@ -0,0 +1,108 @@
+package pkg_postgres
+
+import (
+	"context"
+	"io/fs"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+	"github.com/rs/zerolog/log"
+)
+
+// Migrator runs goose migrations from fsys. Every operation holds a Postgres
+// advisory lock so that several replicas starting together do not race.
+type Migrator struct {
+	provider *goose.Provider
+}
+
+func NewMigrator(db *sqlx.DB, fsys fs.FS) (*Migrator, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, db.DB, fsys, goose.WithSessionLocker(locker))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		provider: provider,
+	}, nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	results, err := m.provider.Up(ctx)
+	logResults(results)
+
+	return err
+}
+
+// Down rolls back the latest applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	result, err := m.provider.Down(ctx)
+	logResults([]*goose.MigrationResult{result})
+
+	return err
+}
+
+// Redo rolls back the latest applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) error {
+	if err := m.Down(ctx); err != nil {
+		return err
+	}
+
+	result, err := m.provider.UpByOne(ctx)
+	logResults([]*goose.MigrationResult{result})
+
+	return err
+}
+
+// Status logs the state of every known migration.
+func (m *Migrator) Status(ctx context.Context) error {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		event := log.Info().
+			Str("migration", status.Source.Path).
+			Str("state", string(status.State))
+		if !status.AppliedAt.IsZero() {
+			event = event.Time("applied_at", status.AppliedAt)
+		}
+		event.Msg("pkg_postgres.Migrator.Status")
+	}
+
+	return nil
+}
+
+// Version returns the current version of the database schema.
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	return m.provider.GetDBVersion(ctx)
+}
+
+func logResults(results []*goose.MigrationResult) {
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		if result.Error != nil {
+			log.Error().Err(result.Error).
+				Str("migration", result.Source.Path).
+				Str("direction", result.Direction).
+				Msg("pkg_postgres.Migrator: migration failed")
+			continue
+		}
+
+		log.Info().
+			Str("migration", result.Source.Path).
+			Str("direction", result.Direction).
+			Dur("duration", result.Duration).
+			Msg("pkg_postgres.Migrator: migration applied")
+	}
+}
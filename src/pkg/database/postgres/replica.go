@@ -0,0 +1,212 @@
+package pkg_postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+type ReplicaSelection string
+
+const (
+	ReplicaSelectionRoundRobin   ReplicaSelection = "round-robin"
+	ReplicaSelectionLeastLatency ReplicaSelection = "least-latency"
+)
+
+// replicaPingTimeout bounds each health check ping, independently of the
+// interval between checks.
+const replicaPingTimeout = 2 * time.Second
+
+type forcePrimaryKey struct{}
+
+// WithPrimary returns a context whose reads are routed to the primary, e.g. to
+// read your own writes right after a mutation.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forcePrimaryKey{}, true)
+}
+
+// IsPrimaryForced reports whether reads on ctx must go to the primary.
+func IsPrimaryForced(ctx context.Context) bool {
+	forced, _ := ctx.Value(forcePrimaryKey{}).(bool)
+	return forced
+}
+
+type replica struct {
+	db      *sqlx.DB
+	healthy atomic.Bool
+	latency atomic.Int64
+}
+
+// ReplicaSet routes reads across read replicas and ejects the ones failing health checks.
+type ReplicaSet struct {
+	replicas  []*replica
+	selection ReplicaSelection
+	next      atomic.Uint64
+}
+
+// NewReplicaSet opens the replicas of cfg and checks their health every
+// ReplicaHealthCheckInterval until ctx is done. Without replicas, it returns an
+// empty set routing every read to the primary.
+func NewReplicaSet(ctx context.Context, cfg *PostgresConfig) (*ReplicaSet, error) {
+	if len(cfg.ReplicaDSNs) == 0 {
+		return &ReplicaSet{}, nil
+	}
+
+	selection := ReplicaSelection(cfg.ReplicaSelection)
+	switch selection {
+	case "":
+		selection = ReplicaSelectionRoundRobin
+	case ReplicaSelectionRoundRobin, ReplicaSelectionLeastLatency:
+	default:
+		return nil, fmt.Errorf("pkg_postgres.NewReplicaSet: unknown replica selection: %s", cfg.ReplicaSelection)
+	}
+
+	rs := &ReplicaSet{
+		selection: selection,
+	}
+
+	for _, dsn := range cfg.ReplicaDSNs {
+		// replicas are opened lazily so that one being down does not prevent startup
+		db, err := sqlx.Open("postgres", cfg.withOptions(dsn))
+		if err != nil {
+			rs.Close()
+			return nil, err
+		}
+		ConfigurePool(db, cfg)
+
+		rs.replicas = append(rs.replicas, &replica{db: db})
+	}
+
+	interval := cfg.ReplicaHealthCheckInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	rs.checkHealth(ctx)
+	go rs.runHealthChecks(ctx, interval)
+
+	return rs, nil
+}
+
+// NewReplicaSetFromDB builds a replica set over already opened connections, all considered healthy.
+func NewReplicaSetFromDB(selection ReplicaSelection, dbs ...*sqlx.DB) *ReplicaSet {
+	rs := &ReplicaSet{
+		selection: selection,
+	}
+
+	for _, db := range dbs {
+		r := &replica{db: db}
+		r.healthy.Store(true)
+		rs.replicas = append(rs.replicas, r)
+	}
+
+	return rs
+}
+
+// Reader returns the replica to use for a read, or nil when the read must go to
+// the primary: no replica configured or healthy, or primary forced on ctx.
+func (rs *ReplicaSet) Reader(ctx context.Context) *sqlx.DB {
+	if rs == nil || len(rs.replicas) == 0 || IsPrimaryForced(ctx) {
+		return nil
+	}
+
+	switch rs.selection {
+	case ReplicaSelectionLeastLatency:
+		var best *replica
+		for _, r := range rs.replicas {
+			if !r.healthy.Load() {
+				continue
+			}
+			if best == nil || r.latency.Load() < best.latency.Load() {
+				best = r
+			}
+		}
+		if best == nil {
+			return nil
+		}
+
+		return best.db
+	default:
+		start := rs.next.Add(1)
+		for i := 0; i < len(rs.replicas); i++ {
+			r := rs.replicas[(start+uint64(i))%uint64(len(rs.replicas))]
+			if r.healthy.Load() {
+				return r.db
+			}
+		}
+
+		return nil
+	}
+}
+
+func (rs *ReplicaSet) Close() error {
+	if rs == nil {
+		return nil
+	}
+
+	var closeErr error
+	for _, r := range rs.replicas {
+		if err := r.db.Close(); err != nil {
+			closeErr = err
+		}
+	}
+
+	return closeErr
+}
+
+func (rs *ReplicaSet) runHealthChecks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rs.checkHealth(ctx)
+		}
+	}
+}
+
+// checkHealth pings the replicas in parallel, so that one being unreachable
+// does not delay the others.
+func (rs *ReplicaSet) checkHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for i, r := range rs.replicas {
+		wg.Add(1)
+		go func(i int, r *replica) {
+			defer wg.Done()
+			r.checkHealth(ctx, i)
+		}(i, r)
+	}
+
+	wg.Wait()
+}
+
+func (r *replica) checkHealth(ctx context.Context, i int) {
+	pingCtx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+	defer cancel()
+
+	start := time.Now()
+	if err := r.db.PingContext(pingCtx); err != nil {
+		if r.healthy.Swap(false) {
+			log.Error().Err(err).
+				Int("replica", i).
+				Msg("pkg_postgres.ReplicaSet.checkHealth: ejecting unhealthy replica")
+		}
+		return
+	}
+
+	r.latency.Store(int64(time.Since(start)))
+	if !r.healthy.Swap(true) {
+		log.Info().
+			Int("replica", i).
+			Msg("pkg_postgres.ReplicaSet.checkHealth: replica is healthy")
+	}
+}
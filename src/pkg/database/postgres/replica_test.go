@@ -0,0 +1,95 @@
+package pkg_postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewReplicaSet(t *testing.T) {
+	t.Run("ok - no replica", func(t *testing.T) {
+		rs, err := NewReplicaSet(context.Background(), &PostgresConfig{})
+		assert.NoError(t, err)
+		assert.NotNil(t, rs)
+
+		assert.Nil(t, rs.Reader(context.Background()))
+		assert.NoError(t, rs.Close())
+	})
+	t.Run("nok - unknown selection", func(t *testing.T) {
+		rs, err := NewReplicaSet(context.Background(), &PostgresConfig{
+			ReplicaDSNs:      []string{"postgres://replica"},
+			ReplicaSelection: "random",
+		})
+		assert.Nil(t, rs)
+		assert.Error(t, err)
+	})
+}
+
+func Test_ReplicaSet_Reader(t *testing.T) {
+	first, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer first.Close()
+	second, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer second.Close()
+
+	rs := NewReplicaSetFromDB(ReplicaSelectionRoundRobin, sqlx.NewDb(first, "sqlmock"), sqlx.NewDb(second, "sqlmock"))
+
+	t.Run("ok - skip unhealthy replicas", func(t *testing.T) {
+		rs.replicas[0].healthy.Store(false)
+		defer rs.replicas[0].healthy.Store(true)
+
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, rs.replicas[1].db, rs.Reader(context.Background()))
+		}
+	})
+	t.Run("ok - primary forced", func(t *testing.T) {
+		assert.Nil(t, rs.Reader(WithPrimary(context.Background())))
+	})
+}
+
+func Test_ReplicaSet_checkHealth(t *testing.T) {
+	t.Run("ok - ping replicas in parallel", func(t *testing.T) {
+		var dbs []*sqlx.DB
+		for i := 0; i < 3; i++ {
+			db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+			assert.NoError(t, err)
+			defer db.Close()
+
+			// unreachable replicas never answer
+			mock.ExpectPing().WillDelayFor(time.Hour)
+			dbs = append(dbs, sqlx.NewDb(db, "sqlmock"))
+		}
+
+		rs := NewReplicaSetFromDB(ReplicaSelectionRoundRobin, dbs...)
+
+		start := time.Now()
+		rs.checkHealth(context.Background())
+
+		assert.Less(t, time.Since(start), 2*replicaPingTimeout)
+		assert.Nil(t, rs.Reader(context.Background()))
+	})
+	t.Run("ok - eject failing replicas", func(t *testing.T) {
+		failing, failingMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		assert.NoError(t, err)
+		defer failing.Close()
+		healthy, healthyMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		assert.NoError(t, err)
+		defer healthy.Close()
+
+		failingMock.ExpectPing().WillReturnError(errors.New("connection refused"))
+		healthyMock.ExpectPing()
+
+		rs := NewReplicaSetFromDB(ReplicaSelectionLeastLatency, sqlx.NewDb(failing, "sqlmock"), sqlx.NewDb(healthy, "sqlmock"))
+		rs.checkHealth(context.Background())
+
+		assert.False(t, rs.replicas[0].healthy.Load())
+		assert.True(t, rs.replicas[1].healthy.Load())
+		assert.Equal(t, rs.replicas[1].db, rs.Reader(context.Background()))
+	})
+}
@@ -115,6 +115,48 @@ func IsUnauthorizedError(err error) bool {
 	return ok
 }
 
+// NewForbiddenError return a new ForbiddenError
+func NewForbiddenError(key string) error {
+	return errors.WithStack(&ForbiddenError{
+		ErrorWithKey: ErrorWithKey{
+			Key: key,
+		},
+	})
+}
+
+// ForbiddenError is used when the caller is authenticated but not allowed to perform the action
+type ForbiddenError struct {
+	ErrorWithKey
+}
+
+// IsForbiddenError verify if an error is a ForbiddenError
+func IsForbiddenError(err error) bool {
+	_, ok := errors.Cause(err).(*ForbiddenError)
+
+	return ok
+}
+
+// NewTooManyRequestsError return a new TooManyRequestsError
+func NewTooManyRequestsError(key string) error {
+	return errors.WithStack(&TooManyRequestsError{
+		ErrorWithKey: ErrorWithKey{
+			Key: key,
+		},
+	})
+}
+
+// TooManyRequestsError is used when the caller exceeded its rate limit
+type TooManyRequestsError struct {
+	ErrorWithKey
+}
+
+// IsTooManyRequestsError verify if an error is a TooManyRequestsError
+func IsTooManyRequestsError(err error) bool {
+	_, ok := errors.Cause(err).(*TooManyRequestsError)
+
+	return ok
+}
+
 // NewResourceAlreadyExist return a new ResourceAlreadyExist
 func NewResourceAlreadyCreatedError(key string) error {
 	return errors.WithStack(&ResourceAlreadyCreatedError{
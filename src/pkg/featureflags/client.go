@@ -0,0 +1,59 @@
+package pkg_featureflags
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Client evaluates flags from a provider. Flags that cannot be fetched are off.
+type Client struct {
+	provider    Provider
+	serviceName string
+	environment string
+}
+
+func NewClient(provider Provider, serviceName, environment string) *Client {
+	return &Client{
+		provider:    provider,
+		serviceName: serviceName,
+		environment: environment,
+	}
+}
+
+// EvaluationContext returns an evaluation context for caller in the service of the client.
+func (c *Client) EvaluationContext(caller string) EvaluationContext {
+	return EvaluationContext{
+		ServiceName: c.serviceName,
+		Environment: c.environment,
+		Caller:      caller,
+	}
+}
+
+// IsEnabled reports whether the boolean flag key is on for evalCtx.
+func (c *Client) IsEnabled(ctx context.Context, key string, evalCtx EvaluationContext) bool {
+	flag, err := c.provider.GetFlag(ctx, key)
+	if err != nil {
+		log.Error().Err(err).
+			Str("flag", key).
+			Msg("pkg_featureflags.Client.IsEnabled: unable to get flag")
+		return false
+	}
+
+	enabled, _ := flag.isEnabled(key, evalCtx)
+
+	return enabled
+}
+
+// Variant returns the variant of the flag key evalCtx gets.
+func (c *Client) Variant(ctx context.Context, key string, evalCtx EvaluationContext) string {
+	flag, err := c.provider.GetFlag(ctx, key)
+	if err != nil {
+		log.Error().Err(err).
+			Str("flag", key).
+			Msg("pkg_featureflags.Client.Variant: unable to get flag")
+		return ""
+	}
+
+	return flag.variant(key, evalCtx)
+}
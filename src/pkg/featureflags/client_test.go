@@ -0,0 +1,179 @@
+package pkg_featureflags
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	cache_mocks "github.com/teyz/go-svc-template/pkg/cache/mocks"
+	"github.com/teyz/go-svc-template/pkg/errors"
+	"go.uber.org/mock/gomock"
+)
+
+func percentage(p int) *int {
+	return &p
+}
+
+func Test_IsEnabled(t *testing.T) {
+	t.Run("ok - flag enabled", func(t *testing.T) {
+		client := NewClient(NewMemoryProvider(Flags{"new-listing": {Enabled: true}}), "go-svc-template", "local")
+
+		assert.True(t, client.IsEnabled(context.Background(), "new-listing", client.EvaluationContext("caller")))
+	})
+	t.Run("ok - flag disabled or unknown", func(t *testing.T) {
+		client := NewClient(NewMemoryProvider(Flags{"new-listing": {Enabled: false}}), "go-svc-template", "local")
+
+		assert.False(t, client.IsEnabled(context.Background(), "new-listing", client.EvaluationContext("caller")))
+		assert.False(t, client.IsEnabled(context.Background(), "unknown", client.EvaluationContext("caller")))
+	})
+	t.Run("ok - flag restricted to environments", func(t *testing.T) {
+		client := NewClient(NewMemoryProvider(Flags{"new-listing": {Enabled: true, Environments: []string{"staging"}}}), "go-svc-template", "local")
+
+		assert.False(t, client.IsEnabled(context.Background(), "new-listing", client.EvaluationContext("caller")))
+	})
+	t.Run("ok - flag rolled out to a percentage of callers", func(t *testing.T) {
+		client := NewClient(NewMemoryProvider(Flags{"new-listing": {Enabled: true, Percentage: percentage(30)}}), "go-svc-template", "local")
+
+		enabled := 0
+		for i := 0; i < 1000; i++ {
+			evalCtx := client.EvaluationContext(fmt.Sprintf("caller-%d", i))
+			first := client.IsEnabled(context.Background(), "new-listing", evalCtx)
+			assert.Equal(t, first, client.IsEnabled(context.Background(), "new-listing", evalCtx))
+			if first {
+				enabled++
+			}
+		}
+
+		assert.InDelta(t, 300, enabled, 60)
+	})
+}
+
+func Test_Variant(t *testing.T) {
+	t.Run("ok - get variant", func(t *testing.T) {
+		client := NewClient(NewMemoryProvider(Flags{"listing-layout": {
+			Enabled:        true,
+			Variants:       []Variant{{Name: "grid", Weight: 50}, {Name: "list", Weight: 50}},
+			DefaultVariant: "list",
+		}}), "go-svc-template", "local")
+
+		seen := map[string]int{}
+		for i := 0; i < 1000; i++ {
+			seen[client.Variant(context.Background(), "listing-layout", client.EvaluationContext(fmt.Sprintf("caller-%d", i)))]++
+		}
+
+		assert.Len(t, seen, 2)
+		assert.InDelta(t, 500, seen["grid"], 100)
+	})
+	t.Run("ok - get default variant when disabled", func(t *testing.T) {
+		client := NewClient(NewMemoryProvider(Flags{"listing-layout": {
+			Variants:       []Variant{{Name: "grid", Weight: 100}},
+			DefaultVariant: "list",
+		}}), "go-svc-template", "local")
+
+		assert.Equal(t, "list", client.Variant(context.Background(), "listing-layout", client.EvaluationContext("caller")))
+	})
+}
+
+func Test_RedisProvider(t *testing.T) {
+	t.Run("ok - get flag from redis", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		mock_cache.EXPECT().HGetAll(gomock.Any(), "feature-flags").Return(map[string]string{
+			"new-listing": `{"enabled": true}`,
+		}, nil).Times(1)
+
+		client := NewClient(NewRedisProvider(mock_cache, "feature-flags", time.Minute), "go-svc-template", "local")
+
+		assert.True(t, client.IsEnabled(context.Background(), "new-listing", client.EvaluationContext("caller")))
+		assert.True(t, client.IsEnabled(context.Background(), "new-listing", client.EvaluationContext("caller")))
+	})
+	t.Run("nok - get flag from redis", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		mock_cache.EXPECT().HGetAll(gomock.Any(), "feature-flags").Return(nil, errors.NewInternalServerError("error"))
+
+		client := NewClient(NewRedisProvider(mock_cache, "feature-flags", time.Minute), "go-svc-template", "local")
+
+		assert.False(t, client.IsEnabled(context.Background(), "new-listing", client.EvaluationContext("caller")))
+	})
+	t.Run("nok - do not fetch again right after a failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		mock_cache.EXPECT().HGetAll(gomock.Any(), "feature-flags").Return(nil, errors.NewInternalServerError("error")).Times(1)
+
+		provider := NewRedisProvider(mock_cache, "feature-flags", time.Minute)
+
+		for i := 0; i < 3; i++ {
+			flag, err := provider.GetFlag(context.Background(), "new-listing")
+			assert.Nil(t, flag)
+			assert.Error(t, err)
+		}
+	})
+	t.Run("ok - serve known flags while refreshing in the background", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		fetched := make(chan struct{})
+		gomock.InOrder(
+			mock_cache.EXPECT().HGetAll(gomock.Any(), "feature-flags").Return(map[string]string{
+				"new-listing": `{"enabled": true}`,
+			}, nil),
+			mock_cache.EXPECT().HGetAll(gomock.Any(), "feature-flags").DoAndReturn(func(ctx context.Context, key string) (map[string]string, error) {
+				<-fetched
+				return nil, errors.NewInternalServerError("error")
+			}),
+		)
+
+		provider := NewRedisProvider(mock_cache, "feature-flags", time.Minute)
+
+		flag, err := provider.GetFlag(context.Background(), "new-listing")
+		assert.NoError(t, err)
+		assert.True(t, flag.Enabled)
+
+		provider.mu.Lock()
+		provider.nextRefreshAt = time.Now()
+		provider.mu.Unlock()
+
+		// served while the refresh is still blocked
+		flag, err = provider.GetFlag(context.Background(), "new-listing")
+		assert.NoError(t, err)
+		assert.True(t, flag.Enabled)
+
+		close(fetched)
+		assert.Eventually(t, func() bool {
+			provider.mu.RLock()
+			defer provider.mu.RUnlock()
+			return provider.failures == 1
+		}, time.Second, 10*time.Millisecond)
+
+		flag, err = provider.GetFlag(context.Background(), "new-listing")
+		assert.NoError(t, err)
+		assert.True(t, flag.Enabled)
+	})
+}
+
+func Test_RedisProvider_retryDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 1, want: time.Second},
+		{failures: 2, want: 2 * time.Second},
+		{failures: 4, want: 8 * time.Second},
+		{failures: 5, want: 10 * time.Second},
+		{failures: 100, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("ok - %d failures", tt.failures), func(t *testing.T) {
+			provider := &RedisProvider{refreshInterval: 10 * time.Second, failures: tt.failures}
+
+			assert.Equal(t, tt.want, provider.retryDelay())
+		})
+	}
+}
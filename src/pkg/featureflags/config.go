@@ -0,0 +1,37 @@
+package pkg_featureflags
+
+import (
+	"fmt"
+	"time"
+
+	pkg_cache "github.com/teyz/go-svc-template/pkg/cache"
+)
+
+const (
+	ProviderConfig = "config"
+	ProviderRedis  = "redis"
+	ProviderMemory = "memory"
+)
+
+type FeatureFlagsConfig struct {
+	Provider string `env:"FEATURE_FLAGS_PROVIDER" envDefault:"config" validate:"oneof=config redis memory" reload:"false"`
+	// Flags is a JSON object of flag definitions used by the config provider
+	Flags                Flags         `env:"FEATURE_FLAGS"`
+	RedisKey             string        `env:"FEATURE_FLAGS_REDIS_KEY" envDefault:"feature-flags"`
+	RedisRefreshInterval time.Duration `env:"FEATURE_FLAGS_REDIS_REFRESH_INTERVAL" envDefault:"10s" validate:"min=0s"`
+}
+
+// NewProvider returns the provider selected by the config. The config and memory
+// providers are both served from memory, the former being seeded with cfg.Flags.
+func NewProvider(cfg *FeatureFlagsConfig, cache pkg_cache.Cache) (Provider, error) {
+	switch cfg.Provider {
+	case ProviderConfig, "":
+		return NewMemoryProvider(cfg.Flags), nil
+	case ProviderMemory:
+		return NewMemoryProvider(nil), nil
+	case ProviderRedis:
+		return NewRedisProvider(cache, cfg.RedisKey, cfg.RedisRefreshInterval), nil
+	default:
+		return nil, fmt.Errorf("pkg_featureflags.NewProvider: unknown provider: %s", cfg.Provider)
+	}
+}
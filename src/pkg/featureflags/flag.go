@@ -0,0 +1,120 @@
+package pkg_featureflags
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"slices"
+)
+
+const (
+	AttributeCaller      = "caller"
+	AttributeService     = "service"
+	AttributeEnvironment = "environment"
+)
+
+type Variant struct {
+	Name   string `json:"name"`
+	Weight int    `json:"weight"`
+}
+
+type Flag struct {
+	Enabled bool `json:"enabled"`
+	// Percentage of evaluation contexts the flag is enabled for, all of them when nil
+	Percentage *int `json:"percentage,omitempty"`
+	// Attribute of the evaluation context hashed for the rollout, the caller by default
+	Attribute string `json:"attribute,omitempty"`
+	// Environments the flag is restricted to, all of them when empty
+	Environments   []string  `json:"environments,omitempty"`
+	Variants       []Variant `json:"variants,omitempty"`
+	DefaultVariant string    `json:"default_variant,omitempty"`
+}
+
+// Flags maps flag keys to their definition, it is read from a JSON object.
+type Flags map[string]*Flag
+
+func (f *Flags) UnmarshalText(text []byte) error {
+	return json.Unmarshal(text, (*map[string]*Flag)(f))
+}
+
+func (f Flags) String() string {
+	bytes, _ := json.Marshal(map[string]*Flag(f))
+	return string(bytes)
+}
+
+// EvaluationContext describes who a flag is evaluated for.
+type EvaluationContext struct {
+	ServiceName string
+	Environment string
+	Caller      string
+	Attributes  map[string]string
+}
+
+// Value returns the value of the given attribute.
+func (e EvaluationContext) Value(attribute string) string {
+	switch attribute {
+	case "", AttributeCaller:
+		return e.Caller
+	case AttributeService:
+		return e.ServiceName
+	case AttributeEnvironment:
+		return e.Environment
+	default:
+		return e.Attributes[attribute]
+	}
+}
+
+// isEnabled reports whether the flag is on for evalCtx, along with the bucket
+// in [0, 100) the context falls in.
+func (f *Flag) isEnabled(key string, evalCtx EvaluationContext) (bool, uint32) {
+	if f == nil || !f.Enabled {
+		return false, 0
+	}
+
+	if len(f.Environments) > 0 && !slices.Contains(f.Environments, evalCtx.Environment) {
+		return false, 0
+	}
+
+	bucket := hashBucket(key, evalCtx.Value(f.Attribute))
+	if f.Percentage != nil && int(bucket) >= *f.Percentage {
+		return false, bucket
+	}
+
+	return true, bucket
+}
+
+// variant returns the variant evalCtx gets, the default one when the flag is off.
+func (f *Flag) variant(key string, evalCtx EvaluationContext) string {
+	enabled, _ := f.isEnabled(key, evalCtx)
+	if !enabled || len(f.Variants) == 0 {
+		if f == nil {
+			return ""
+		}
+		return f.DefaultVariant
+	}
+
+	total := 0
+	for _, v := range f.Variants {
+		total += v.Weight
+	}
+	if total <= 0 {
+		return f.DefaultVariant
+	}
+
+	// hashed with a different seed than the rollout so variants spread evenly within it
+	point := int(hashBucket(key+":variant", evalCtx.Value(f.Attribute))) * total / 100
+	for _, v := range f.Variants {
+		if point < v.Weight {
+			return v.Name
+		}
+		point -= v.Weight
+	}
+
+	return f.DefaultVariant
+}
+
+func hashBucket(key, value string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key + ":" + value))
+
+	return h.Sum32() % 100
+}
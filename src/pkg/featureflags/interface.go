@@ -0,0 +1,8 @@
+package pkg_featureflags
+
+import "context"
+
+// Provider returns the definition of a flag, nil when the flag does not exist.
+type Provider interface {
+	GetFlag(ctx context.Context, key string) (*Flag, error)
+}
@@ -0,0 +1,45 @@
+package pkg_featureflags
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryProvider serves flags from memory, mostly for tests.
+type MemoryProvider struct {
+	mu    sync.RWMutex
+	flags Flags
+}
+
+func NewMemoryProvider(flags Flags) *MemoryProvider {
+	p := &MemoryProvider{}
+	p.SetFlags(flags)
+
+	return p
+}
+
+func (p *MemoryProvider) GetFlag(ctx context.Context, key string) (*Flag, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.flags[key], nil
+}
+
+// SetFlags replaces every flag, e.g. when the configuration is reloaded.
+func (p *MemoryProvider) SetFlags(flags Flags) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.flags = make(Flags, len(flags))
+	for key, flag := range flags {
+		p.flags[key] = flag
+	}
+}
+
+// Set defines a single flag.
+func (p *MemoryProvider) Set(key string, flag *Flag) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.flags[key] = flag
+}
@@ -0,0 +1,70 @@
+package pkg_featureflags
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+
+	pkg_auth "github.com/teyz/go-svc-template/pkg/auth"
+)
+
+type evaluationContextKey struct{}
+
+type clientKey struct{}
+
+// WithEvaluationContext returns a copy of ctx carrying evalCtx.
+func WithEvaluationContext(ctx context.Context, evalCtx EvaluationContext) context.Context {
+	return context.WithValue(ctx, evaluationContextKey{}, evalCtx)
+}
+
+// EvaluationContextFromContext returns the evaluation context of the request.
+func EvaluationContextFromContext(ctx context.Context) EvaluationContext {
+	evalCtx, _ := ctx.Value(evaluationContextKey{}).(EvaluationContext)
+	return evalCtx
+}
+
+// Middleware stores the client and the evaluation context of the request in the
+// request context, for handlers to use IsEnabled and GetVariant. Flags are
+// evaluated for the subject of the authenticated caller, or for its IP, so it
+// must run after the authentication middleware.
+func Middleware(client *Client) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			caller := c.RealIP()
+			if claims, ok := pkg_auth.ClaimsFromContext(c.Request().Context()); ok && claims.Subject != "" {
+				caller = claims.Subject
+			}
+
+			ctx := WithEvaluationContext(c.Request().Context(), client.EvaluationContext(caller))
+			ctx = context.WithValue(ctx, clientKey{}, client)
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
+// IsEnabled evaluates the boolean flag key for the request, it is off when the
+// middleware is not installed.
+func IsEnabled(c echo.Context, key string) bool {
+	ctx := c.Request().Context()
+
+	client, ok := ctx.Value(clientKey{}).(*Client)
+	if !ok {
+		return false
+	}
+
+	return client.IsEnabled(ctx, key, EvaluationContextFromContext(ctx))
+}
+
+// GetVariant evaluates the variant flag key for the request.
+func GetVariant(c echo.Context, key string) string {
+	ctx := c.Request().Context()
+
+	client, ok := ctx.Value(clientKey{}).(*Client)
+	if !ok {
+		return ""
+	}
+
+	return client.Variant(ctx, key, EvaluationContextFromContext(ctx))
+}
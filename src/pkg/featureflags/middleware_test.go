@@ -0,0 +1,52 @@
+package pkg_featureflags
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	pkg_auth "github.com/teyz/go-svc-template/pkg/auth"
+)
+
+func Test_Middleware(t *testing.T) {
+	client := NewClient(NewMemoryProvider(Flags{}), "go-svc-template", "local")
+
+	serve := func(ctx context.Context, headers map[string]string) EvaluationContext {
+		e := echo.New()
+		e.IPExtractor = echo.ExtractIPDirect()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+		req.RemoteAddr = "10.0.0.1:1234"
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+
+		var evalCtx EvaluationContext
+		handler := Middleware(client)(func(c echo.Context) error {
+			evalCtx = EvaluationContextFromContext(c.Request().Context())
+			return nil
+		})
+		assert.NoError(t, handler(e.NewContext(req, httptest.NewRecorder())))
+
+		return evalCtx
+	}
+
+	t.Run("ok - evaluate flags for the authenticated caller", func(t *testing.T) {
+		claims := &pkg_auth.Claims{}
+		claims.Subject = "user-1"
+
+		evalCtx := serve(pkg_auth.WithClaims(context.Background(), claims), map[string]string{"X-Caller-ID": "user-2"})
+
+		assert.Equal(t, "user-1", evalCtx.Caller)
+		assert.Equal(t, "local", evalCtx.Environment)
+	})
+	t.Run("ok - evaluate flags for the IP of anonymous callers", func(t *testing.T) {
+		evalCtx := serve(context.Background(), map[string]string{"X-Caller-ID": "user-2", echo.HeaderXForwardedFor: "198.51.100.1"})
+
+		assert.Equal(t, "10.0.0.1", evalCtx.Caller)
+	})
+}
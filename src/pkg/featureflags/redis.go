@@ -0,0 +1,134 @@
+package pkg_featureflags
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/singleflight"
+
+	pkg_cache "github.com/teyz/go-svc-template/pkg/cache"
+)
+
+// minRetryDelay is the delay before retrying a failed refresh, doubled on every
+// consecutive failure up to the refresh interval.
+const minRetryDelay = time.Second
+
+// RedisProvider reads flags from a Redis hash whose fields are flag keys and
+// values their JSON definition. The hash is fetched at most once per refresh
+// interval, in the background once the flags are known, the last known flags
+// being served if Redis is unavailable.
+type RedisProvider struct {
+	cache           pkg_cache.Cache
+	key             string
+	refreshInterval time.Duration
+
+	// refreshes collapses the refreshes of concurrent lookups into one
+	refreshes singleflight.Group
+
+	mu            sync.RWMutex
+	flags         Flags
+	err           error
+	failures      int
+	nextRefreshAt time.Time
+}
+
+func NewRedisProvider(cache pkg_cache.Cache, key string, refreshInterval time.Duration) *RedisProvider {
+	return &RedisProvider{
+		cache:           cache,
+		key:             key,
+		refreshInterval: refreshInterval,
+	}
+}
+
+func (p *RedisProvider) GetFlag(ctx context.Context, key string) (*Flag, error) {
+	p.mu.RLock()
+	flags, err := p.flags, p.err
+	due := !time.Now().Before(p.nextRefreshAt)
+	p.mu.RUnlock()
+
+	if !due {
+		if flags == nil {
+			return nil, err
+		}
+		return flags[key], nil
+	}
+
+	// the refresh is shared with concurrent lookups, it must not be canceled
+	// along with the request that started it
+	done := p.refreshes.DoChan("refresh", func() (interface{}, error) {
+		return nil, p.refresh(context.WithoutCancel(ctx))
+	})
+	if flags != nil {
+		return flags[key], nil
+	}
+
+	// nothing to serve until the flags are fetched once
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-done:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.flags[key], nil
+}
+
+func (p *RedisProvider) refresh(ctx context.Context) error {
+	values, err := p.cache.HGetAll(ctx, p.key)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err != nil {
+		p.err = err
+		p.failures++
+		p.nextRefreshAt = time.Now().Add(p.retryDelay())
+
+		log.Error().Err(err).
+			Int("failures", p.failures).
+			Msg("pkg_featureflags.RedisProvider.refresh: unable to fetch flags")
+		return err
+	}
+
+	flags := make(Flags, len(values))
+	for key, value := range values {
+		flag := &Flag{}
+		if err := json.Unmarshal([]byte(value), flag); err != nil {
+			log.Error().Err(err).
+				Str("flag", key).
+				Msg("pkg_featureflags.RedisProvider.refresh: unable to unmarshal flag")
+			continue
+		}
+		flags[key] = flag
+	}
+
+	p.flags = flags
+	p.err = nil
+	p.failures = 0
+	p.nextRefreshAt = time.Now().Add(p.refreshInterval)
+
+	return nil
+}
+
+// retryDelay returns the delay before the next refresh after p.failures
+// consecutive failures.
+func (p *RedisProvider) retryDelay() time.Duration {
+	delay := minRetryDelay
+	for i := 1; i < p.failures && delay < p.refreshInterval; i++ {
+		delay *= 2
+	}
+
+	if delay > p.refreshInterval {
+		return p.refreshInterval
+	}
+
+	return delay
+}
@@ -0,0 +1,102 @@
+package pkg_http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderETag            = "ETag"
+	HeaderIfNoneMatch     = "If-None-Match"
+	HeaderIfModifiedSince = "If-Modified-Since"
+)
+
+// CacheControl lets browsers keep successful GET and HEAD responses for maxAge
+// and shared caches for sharedMaxAge. Errors are never stored.
+func CacheControl(maxAge time.Duration, sharedMaxAge time.Duration) echo.MiddlewareFunc {
+	value := fmt.Sprintf("public, max-age=%d, s-maxage=%d", int(maxAge.Seconds()), int(sharedMaxAge.Seconds()))
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			method := c.Request().Method
+			if method != http.MethodGet && method != http.MethodHead {
+				return next(c)
+			}
+
+			c.Response().Before(func() {
+				if c.Response().Status < http.StatusBadRequest {
+					c.Response().Header().Set(echo.HeaderCacheControl, value)
+				} else {
+					c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+				}
+			})
+
+			return next(c)
+		}
+	}
+}
+
+// CacheableJSON answers with i as JSON along with its validators: an ETag
+// computed from the body and, unless zero, lastModified. Requests whose
+// If-None-Match or If-Modified-Since show they already hold the response get
+// a 304 without body.
+func CacheableJSON(c echo.Context, code int, i interface{}, lastModified time.Time) error {
+	body, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Response().Header().Set(HeaderETag, etag)
+	if !lastModified.IsZero() {
+		c.Response().Header().Set(echo.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request(), etag, lastModified) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.JSONBlob(code, body)
+}
+
+// notModified evaluates the conditions of a request, If-None-Match taking
+// precedence over If-Modified-Since as required by RFC 9110.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if header := r.Header.Get(HeaderIfNoneMatch); header != "" {
+		return etagMatches(header, etag)
+	}
+
+	if header := r.Header.Get(HeaderIfModifiedSince); header != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(header)
+		if err != nil {
+			return false
+		}
+
+		// Last-Modified only has a precision of a second
+		return !lastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, with the weak
+// comparison used for GET requests.
+func etagMatches(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,77 @@
+package pkg_http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveCacheable(status int, lastModified time.Time, headers map[string]string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error {
+		if status != http.StatusOK {
+			return c.JSON(status, NewHTTPResponse(status, MessageNotFoundError, nil))
+		}
+		return CacheableJSON(c, http.StatusOK, NewHTTPResponse(http.StatusOK, MessageSuccess, "data"), lastModified)
+	}, CacheControl(time.Minute, 5*time.Minute))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func Test_CacheableJSON(t *testing.T) {
+	lastModified := time.Date(2026, 10, 19, 12, 0, 0, 500, time.UTC)
+
+	t.Run("ok - response with validators", func(t *testing.T) {
+		rec := serveCacheable(http.StatusOK, lastModified, nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "public, max-age=60, s-maxage=300", rec.Header().Get(echo.HeaderCacheControl))
+		assert.Equal(t, "Mon, 19 Oct 2026 12:00:00 GMT", rec.Header().Get(echo.HeaderLastModified))
+		assert.NotEmpty(t, rec.Header().Get(HeaderETag))
+		assert.NotEmpty(t, rec.Body.String())
+	})
+	t.Run("ok - not modified with matching etag", func(t *testing.T) {
+		etag := serveCacheable(http.StatusOK, lastModified, nil).Header().Get(HeaderETag)
+
+		rec := serveCacheable(http.StatusOK, lastModified, map[string]string{HeaderIfNoneMatch: `"other", W/` + etag})
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Equal(t, etag, rec.Header().Get(HeaderETag))
+		assert.Empty(t, rec.Body.String())
+	})
+	t.Run("ok - modified with another etag despite the date", func(t *testing.T) {
+		rec := serveCacheable(http.StatusOK, lastModified, map[string]string{
+			HeaderIfNoneMatch:     `"other"`,
+			HeaderIfModifiedSince: lastModified.Format(http.TimeFormat),
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("ok - not modified since", func(t *testing.T) {
+		rec := serveCacheable(http.StatusOK, lastModified, map[string]string{HeaderIfModifiedSince: lastModified.Format(http.TimeFormat)})
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+	t.Run("ok - modified since", func(t *testing.T) {
+		rec := serveCacheable(http.StatusOK, lastModified, map[string]string{HeaderIfModifiedSince: lastModified.Add(-time.Minute).Format(http.TimeFormat)})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("nok - errors are not stored", func(t *testing.T) {
+		rec := serveCacheable(http.StatusNotFound, lastModified, nil)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "no-store", rec.Header().Get(echo.HeaderCacheControl))
+	})
+}
@@ -1,5 +1,184 @@
 package pkg_http
 
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/bytes"
+)
+
 type HTTPServerConfig struct {
-	Port uint16 `env:"HTTP_SERVER_PORT"`
+	Port uint16 `env:"HTTP_SERVER_PORT,required" validate:"min=1" reload:"false"`
+
+	// CORSAllowOrigins empty disables CORS, CORSAllowHeaders empty allows the
+	// headers requested by preflight requests
+	CORSAllowOrigins []string `env:"HTTP_CORS_ALLOW_ORIGINS" envSeparator:"," reload:"false"`
+	CORSAllowMethods []string `env:"HTTP_CORS_ALLOW_METHODS" envDefault:"GET,HEAD,PUT,PATCH,POST,DELETE" envSeparator:"," reload:"false"`
+	CORSAllowHeaders []string `env:"HTTP_CORS_ALLOW_HEADERS" envSeparator:"," reload:"false"`
+
+	// HSTS is only sent on HTTPS requests, including those behind a proxy
+	// setting X-Forwarded-Proto
+	HSTSMaxAge   int    `env:"HTTP_HSTS_MAX_AGE" envDefault:"31536000" validate:"min=0" reload:"false"`
+	FrameOptions string `env:"HTTP_FRAME_OPTIONS" envDefault:"DENY" validate:"oneof=DENY SAMEORIGIN" reload:"false"`
+
+	// body limits are sizes such as 512K or 1M, uploads having their own
+	BodyLimit       string `env:"HTTP_BODY_LIMIT" envDefault:"1M" reload:"false"`
+	UploadBodyLimit string `env:"HTTP_UPLOAD_BODY_LIMIT" envDefault:"11M" reload:"false"`
+
+	// TrustedProxies are the CIDRs of the proxies whose X-Forwarded-For is
+	// trusted, the IP of a client being the one it connects from otherwise
+	TrustedProxies []string `env:"HTTP_TRUSTED_PROXIES" envSeparator:"," reload:"false"`
+
+	// responses shorter than GzipMinLength bytes are not compressed
+	GzipMinLength int `env:"HTTP_GZIP_MIN_LENGTH" envDefault:"1024" validate:"min=0" reload:"false"`
+
+	ReadTimeout       time.Duration `env:"HTTP_READ_TIMEOUT" envDefault:"30s" validate:"min=0s" reload:"false"`
+	ReadHeaderTimeout time.Duration `env:"HTTP_READ_HEADER_TIMEOUT" envDefault:"5s" validate:"min=0s" reload:"false"`
+	WriteTimeout      time.Duration `env:"HTTP_WRITE_TIMEOUT" envDefault:"60s" validate:"min=0s" reload:"false"`
+	IdleTimeout       time.Duration `env:"HTTP_IDLE_TIMEOUT" envDefault:"120s" validate:"min=0s" reload:"false"`
+
+	Public PublicConfig `reload:"false"`
+	TLS    TLSConfig    `reload:"false"`
+}
+
+// Validate checks that the body limits are valid sizes.
+func (cfg *HTTPServerConfig) Validate() error {
+	if _, err := bytes.Parse(cfg.BodyLimit); err != nil {
+		return fmt.Errorf("env: invalid HTTP_BODY_LIMIT %q: %w", cfg.BodyLimit, err)
+	}
+
+	if _, err := bytes.Parse(cfg.UploadBodyLimit); err != nil {
+		return fmt.Errorf("env: invalid HTTP_UPLOAD_BODY_LIMIT %q: %w", cfg.UploadBodyLimit, err)
+	}
+
+	if _, err := trustedProxies(cfg.TrustedProxies); err != nil {
+		return err
+	}
+
+	return cfg.TLS.Validate()
+}
+
+// IPExtractor returns how echo finds the IP of a client: the address it
+// connects from, or the last untrusted address of X-Forwarded-For when it
+// connects through TrustedProxies.
+func (cfg *HTTPServerConfig) IPExtractor() echo.IPExtractor {
+	proxies, _ := trustedProxies(cfg.TrustedProxies)
+	if len(proxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, proxy := range proxies {
+		options = append(options, echo.TrustIPRange(proxy))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+// trustedProxies parses the CIDRs of the trusted proxies.
+func trustedProxies(cidrs []string) ([]*net.IPNet, error) {
+	proxies := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, proxy, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("env: invalid CIDR %q in HTTP_TRUSTED_PROXIES: %w", cidr, err)
+		}
+		proxies = append(proxies, proxy)
+	}
+
+	return proxies, nil
+}
+
+// PublicConfig configures the public endpoints, which are cached by browsers
+// for CacheMaxAge and by shared caches such as CDNs for CacheSharedMaxAge.
+type PublicConfig struct {
+	CORSAllowOrigins  []string      `env:"PUBLIC_CORS_ALLOW_ORIGINS" envDefault:"*" envSeparator:","`
+	CacheMaxAge       time.Duration `env:"PUBLIC_CACHE_MAX_AGE" envDefault:"1m" validate:"min=0s"`
+	CacheSharedMaxAge time.Duration `env:"PUBLIC_CACHE_SHARED_MAX_AGE" envDefault:"5m" validate:"min=0s"`
+}
+
+// TLSConfig enables HTTPS and HTTP/2 when CertFile is set. The certificate, key
+// and client CA bundle are reloaded when they change on disk. Setting
+// ClientCAFile enables mutual TLS, ClientAuth telling whether a client
+// certificate is required or only verified when given.
+type TLSConfig struct {
+	CertFile     string   `env:"HTTP_TLS_CERT_FILE"`
+	KeyFile      string   `env:"HTTP_TLS_KEY_FILE"`
+	MinVersion   string   `env:"HTTP_TLS_MIN_VERSION" envDefault:"1.2" validate:"oneof=1.2 1.3"`
+	CipherSuites []string `env:"HTTP_TLS_CIPHER_SUITES" envSeparator:","`
+	ClientCAFile string   `env:"HTTP_TLS_CLIENT_CA_FILE"`
+	ClientAuth   string   `env:"HTTP_TLS_CLIENT_AUTH" envDefault:"require" validate:"oneof=require verify_if_given"`
+}
+
+// Enabled reports whether the server is served over TLS.
+func (cfg *TLSConfig) Enabled() bool {
+	return cfg.CertFile != ""
+}
+
+// Validate checks that the certificate comes with its key and that the cipher
+// suites are known.
+func (cfg *TLSConfig) Validate() error {
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return errors.New("env: HTTP_TLS_CERT_FILE and HTTP_TLS_KEY_FILE must be set together")
+	}
+
+	if cfg.ClientCAFile != "" && !cfg.Enabled() {
+		return errors.New("env: HTTP_TLS_CLIENT_CA_FILE requires HTTP_TLS_CERT_FILE")
+	}
+
+	if _, err := cipherSuites(cfg.CipherSuites); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// minVersion returns the tls version of MinVersion.
+func (cfg *TLSConfig) minVersion() uint16 {
+	if cfg.MinVersion == "1.3" {
+		return tls.VersionTLS13
+	}
+
+	return tls.VersionTLS12
+}
+
+// clientAuth returns the tls client authentication of ClientAuth, none when
+// there is no client CA bundle.
+func (cfg *TLSConfig) clientAuth() tls.ClientAuthType {
+	switch {
+	case cfg.ClientCAFile == "":
+		return tls.NoClientCert
+	case cfg.ClientAuth == "verify_if_given":
+		return tls.VerifyClientCertIfGiven
+	default:
+		return tls.RequireAndVerifyClientCert
+	}
+}
+
+// cipherSuites returns the ids of the secure cipher suites named as in
+// crypto/tls, nil letting Go pick them. They only apply up to TLS 1.2.
+func cipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	known := make(map[string]uint16, len(tls.CipherSuites()))
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("env: unknown or insecure cipher suite %q in HTTP_TLS_CIPHER_SUITES", name)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
 }
@@ -0,0 +1,64 @@
+package pkg_http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HTTPServerConfig_Validate(t *testing.T) {
+	t.Run("ok - body limits", func(t *testing.T) {
+		cfg := HTTPServerConfig{BodyLimit: "512K", UploadBodyLimit: "11M"}
+
+		assert.NoError(t, cfg.Validate())
+	})
+	t.Run("nok - invalid body limit", func(t *testing.T) {
+		cfg := HTTPServerConfig{BodyLimit: "lots", UploadBodyLimit: "11M"}
+
+		assert.ErrorContains(t, cfg.Validate(), "HTTP_BODY_LIMIT")
+	})
+	t.Run("nok - invalid upload body limit", func(t *testing.T) {
+		cfg := HTTPServerConfig{BodyLimit: "1M", UploadBodyLimit: "11 potatoes"}
+
+		assert.ErrorContains(t, cfg.Validate(), "HTTP_UPLOAD_BODY_LIMIT")
+	})
+	t.Run("nok - invalid trusted proxy", func(t *testing.T) {
+		cfg := HTTPServerConfig{BodyLimit: "1M", UploadBodyLimit: "11M", TrustedProxies: []string{"10.0.0.1"}}
+
+		assert.ErrorContains(t, cfg.Validate(), "HTTP_TRUSTED_PROXIES")
+	})
+	t.Run("nok - invalid TLS configuration", func(t *testing.T) {
+		cfg := HTTPServerConfig{BodyLimit: "1M", UploadBodyLimit: "11M", TLS: TLSConfig{CertFile: "tls.crt"}}
+
+		assert.Error(t, cfg.Validate())
+	})
+}
+
+func Test_HTTPServerConfig_IPExtractor(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		want           string
+	}{
+		{name: "ok - ignore forwarded IPs without trusted proxy", remoteAddr: "10.0.0.2:1234", want: "10.0.0.2"},
+		{name: "ok - forwarded IP through a trusted proxy", trustedProxies: []string{"10.0.0.0/24"}, remoteAddr: "10.0.0.2:1234", want: "203.0.113.2"},
+		{name: "ok - ignore forwarded IPs of an untrusted proxy", trustedProxies: []string{"10.0.1.0/24"}, remoteAddr: "10.0.0.2:1234", want: "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := HTTPServerConfig{TrustedProxies: tt.trustedProxies}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			// the client sets the first address, the trusted proxy appends the one it sees
+			req.Header.Set(echo.HeaderXForwardedFor, "198.51.100.1, 203.0.113.2")
+
+			assert.Equal(t, tt.want, cfg.IPExtractor()(req))
+		})
+	}
+}
@@ -17,6 +17,10 @@ func TranslateError(ctx context.Context, err error) (int, interface{}) {
 		return http.StatusBadRequest, NewHTTPResponse(http.StatusBadRequest, err.Error(), nil)
 	case errors.IsUnauthorizedError(err):
 		return http.StatusUnauthorized, NewHTTPResponse(http.StatusUnauthorized, err.Error(), nil)
+	case errors.IsForbiddenError(err):
+		return http.StatusForbidden, NewHTTPResponse(http.StatusForbidden, err.Error(), nil)
+	case errors.IsTooManyRequestsError(err):
+		return http.StatusTooManyRequests, NewHTTPResponse(http.StatusTooManyRequests, err.Error(), nil)
 	default:
 		return http.StatusInternalServerError, NewHTTPResponse(http.StatusInternalServerError, MessageInternalServerError, nil)
 	}
@@ -0,0 +1,48 @@
+package pkg_http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// ClearWriteDeadline lifts the write timeout of the server on the requests
+// matched by stream, whose responses are streamed for as long as they take. It
+// must run before any middleware wrapping the response writer, such as gzip.
+func ClearWriteDeadline(stream func(c echo.Context) bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if stream(c) {
+				if err := http.NewResponseController(c.Response().Writer).SetWriteDeadline(time.Time{}); err != nil {
+					log.Warn().Err(err).
+						Str("path", c.Path()).
+						Msg("pkg_http.ClearWriteDeadline: unable to clear write deadline")
+				}
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// SecurityHeaders sets X-Content-Type-Options and X-Frame-Options on every
+// response, and Strict-Transport-Security on HTTPS ones.
+func SecurityHeaders(cfg *HTTPServerConfig) echo.MiddlewareFunc {
+	return middleware.SecureWithConfig(middleware.SecureConfig{
+		ContentTypeNosniff: "nosniff",
+		XFrameOptions:      cfg.FrameOptions,
+		HSTSMaxAge:         cfg.HSTSMaxAge,
+	})
+}
+
+// BodyLimit limits request bodies to cfg.BodyLimit, except on the requests
+// matched by upload, which are limited by their route.
+func BodyLimit(cfg *HTTPServerConfig, upload func(c echo.Context) bool) echo.MiddlewareFunc {
+	return middleware.BodyLimitWithConfig(middleware.BodyLimitConfig{
+		Skipper: upload,
+		Limit:   cfg.BodyLimit,
+	})
+}
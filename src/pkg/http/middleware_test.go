@@ -0,0 +1,107 @@
+package pkg_http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ClearWriteDeadline(t *testing.T) {
+	e := echo.New()
+	e.Use(ClearWriteDeadline(func(c echo.Context) bool {
+		return c.Path() == "/stream"
+	}))
+
+	// both handlers answer after the write timeout of the server
+	slow := func(c echo.Context) error {
+		time.Sleep(100 * time.Millisecond)
+		return c.String(http.StatusOK, "done")
+	}
+	e.GET("/stream", slow)
+	e.GET("/other", slow)
+
+	srv := httptest.NewUnstartedServer(e)
+	srv.Config.WriteTimeout = 20 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	t.Run("ok - no write timeout on matched requests", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/stream")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "done", string(body))
+	})
+	t.Run("nok - write timeout on other requests", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/other")
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		assert.Error(t, err)
+	})
+}
+
+func Test_SecurityHeaders(t *testing.T) {
+	e := echo.New()
+	e.Use(SecurityHeaders(&HTTPServerConfig{FrameOptions: "DENY", HSTSMaxAge: 3600}))
+	e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	t.Run("ok - HTTP response", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, "nosniff", rec.Header().Get(echo.HeaderXContentTypeOptions))
+		assert.Equal(t, "DENY", rec.Header().Get(echo.HeaderXFrameOptions))
+		assert.Empty(t, rec.Header().Get(echo.HeaderStrictTransportSecurity))
+	})
+	t.Run("ok - HTTPS response behind a proxy", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderXForwardedProto, "https")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, "max-age=3600; includeSubdomains", rec.Header().Get(echo.HeaderStrictTransportSecurity))
+	})
+}
+
+func Test_BodyLimit(t *testing.T) {
+	e := echo.New()
+	e.Use(BodyLimit(&HTTPServerConfig{BodyLimit: "1K"}, func(c echo.Context) bool {
+		return c.Path() == "/upload"
+	}))
+	read := func(c echo.Context) error {
+		if _, err := io.ReadAll(c.Request().Body); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusOK)
+	}
+	e.POST("/", read)
+	e.POST("/upload", read)
+
+	post := func(path string, size int) int {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(strings.Repeat("a", size))))
+		return rec.Code
+	}
+
+	t.Run("ok - body within the limit", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, post("/", 512))
+	})
+	t.Run("ok - upload over the limit", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, post("/upload", 2048))
+	})
+	t.Run("nok - body over the limit", func(t *testing.T) {
+		assert.Equal(t, http.StatusRequestEntityTooLarge, post("/", 2048))
+	})
+}
@@ -12,6 +12,9 @@ const (
 	MessageNotFoundError           = "NOT_FOUND_ERROR"
 	MessageUnauthorizedError       = "UNAUTHORIZED_ERROR"
 	MessageForbidenError           = "FORBIDEN_ERROR"
+	MessageExpiredCredentialsError = "EXPIRED_CREDENTIALS_ERROR"
+	MessageTooManyRequestsError    = "TOO_MANY_REQUESTS_ERROR"
+	MessageRequestTooLargeError    = "REQUEST_TOO_LARGE_ERROR"
 )
 
 type HTTPResponseStatus struct {
@@ -0,0 +1,193 @@
+package pkg_http
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+)
+
+// Certificates holds the server certificate and the client CA bundle of a
+// TLSConfig, and reloads them when their files change.
+type Certificates struct {
+	config TLSConfig
+
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+	// files are the contents loaded last, to skip reloads changing nothing
+	files [][]byte
+}
+
+// NewCertificates loads the certificate, key and client CA bundle of cfg.
+func NewCertificates(cfg TLSConfig) (*Certificates, error) {
+	c := &Certificates{
+		config: cfg,
+	}
+
+	if _, err := c.Reload(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// TLSConfig returns the tls configuration serving the current certificate and
+// client CA bundle, negotiating HTTP/2 before HTTP/1.1.
+func (c *Certificates) TLSConfig() *tls.Config {
+	// cipher suites are checked when the configuration is validated
+	cipherSuites, _ := cipherSuites(c.config.CipherSuites)
+
+	base := &tls.Config{
+		MinVersion:   c.config.minVersion(),
+		CipherSuites: cipherSuites,
+		NextProtos:   []string{"h2", "http/1.1"},
+		ClientAuth:   c.config.clientAuth(),
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			c.mu.RLock()
+			defer c.mu.RUnlock()
+
+			return c.certificate, nil
+		},
+	}
+
+	if base.ClientAuth == tls.NoClientCert {
+		return base
+	}
+
+	// the client CA bundle is not read through a callback, every handshake gets
+	// a configuration holding the current one
+	config := base.Clone()
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+
+		client := base.Clone()
+		client.ClientCAs = c.clientCAs
+
+		return client, nil
+	}
+
+	return config
+}
+
+// Reload reads the files again and reports whether they changed. The current
+// certificate and client CA bundle are kept when they cannot be loaded.
+func (c *Certificates) Reload() (bool, error) {
+	paths := []string{c.config.CertFile, c.config.KeyFile}
+	if c.config.ClientCAFile != "" {
+		paths = append(paths, c.config.ClientCAFile)
+	}
+
+	files := make([][]byte, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return false, fmt.Errorf("pkg_http.Certificates.Reload: unable to read %s: %w", path, err)
+		}
+		files = append(files, data)
+	}
+
+	c.mu.RLock()
+	unchanged := c.files != nil && equalFiles(c.files, files)
+	c.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	certificate, err := tls.X509KeyPair(files[0], files[1])
+	if err != nil {
+		return false, fmt.Errorf("pkg_http.Certificates.Reload: invalid certificate or key: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if len(files) > 2 {
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(files[2]) {
+			return false, errors.New("pkg_http.Certificates.Reload: no certificate found in client CA bundle")
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.certificate = &certificate
+	c.clientCAs = clientCAs
+	c.files = files
+
+	return true, nil
+}
+
+// Watch reloads the files when they change, until ctx is done.
+func (c *Certificates) Watch(ctx context.Context) error {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer fsWatcher.Close()
+
+	// the directories are watched as Kubernetes swaps a symlink to rotate
+	// mounted secrets rather than write the files
+	dirs := map[string]struct{}{}
+	for _, path := range []string{c.config.CertFile, c.config.KeyFile, c.config.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+
+		dir := filepath.Dir(path)
+		if _, ok := dirs[dir]; ok {
+			continue
+		}
+		dirs[dir] = struct{}{}
+
+		if err := fsWatcher.Add(dir); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-fsWatcher.Events:
+			// a rotation is seen as several events, half written files failing
+			// to load until the last one
+			reloaded, err := c.Reload()
+			if err != nil {
+				log.Warn().Err(err).
+					Msg("pkg_http.Certificates.Watch: unable to reload certificates, keeping the current ones")
+				continue
+			}
+
+			if reloaded {
+				log.Info().
+					Msg("pkg_http.Certificates.Watch: certificates reloaded")
+			}
+		case err := <-fsWatcher.Errors:
+			log.Error().Err(err).
+				Msg("pkg_http.Certificates.Watch: unable to watch certificates")
+		}
+	}
+}
+
+func equalFiles(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
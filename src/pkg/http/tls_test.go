@@ -0,0 +1,226 @@
+package pkg_http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeCertificate writes a certificate for localhost and its key in dir,
+// signed by parent or self-signed when parent is nil, and returns it.
+func writeCertificate(t *testing.T, dir string, name string, parent *tls.Certificate) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+
+	signer, signerKey := template, interface{}(key)
+	if parent != nil {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name+".crt"), certPEM, 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name+".key"), keyPEM, 0o600))
+
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	assert.NoError(t, err)
+	certificate.Leaf, err = x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return certificate
+}
+
+func Test_Certificates(t *testing.T) {
+	t.Run("ok - reload a rotated certificate", func(t *testing.T) {
+		dir := t.TempDir()
+		first := writeCertificate(t, dir, "server", nil)
+
+		certificates, err := NewCertificates(TLSConfig{
+			CertFile: filepath.Join(dir, "server.crt"),
+			KeyFile:  filepath.Join(dir, "server.key"),
+		})
+		assert.NoError(t, err)
+
+		config := certificates.TLSConfig()
+		current, err := config.GetCertificate(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, first.Certificate, current.Certificate)
+
+		reloaded, err := certificates.Reload()
+		assert.NoError(t, err)
+		assert.False(t, reloaded)
+
+		second := writeCertificate(t, dir, "server", nil)
+		reloaded, err = certificates.Reload()
+		assert.NoError(t, err)
+		assert.True(t, reloaded)
+
+		current, err = config.GetCertificate(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, second.Certificate, current.Certificate)
+	})
+	t.Run("nok - keep the current certificate when the new one is invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		first := writeCertificate(t, dir, "server", nil)
+
+		certificates, err := NewCertificates(TLSConfig{
+			CertFile: filepath.Join(dir, "server.crt"),
+			KeyFile:  filepath.Join(dir, "server.key"),
+		})
+		assert.NoError(t, err)
+
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "server.key"), []byte("invalid"), 0o600))
+		reloaded, err := certificates.Reload()
+		assert.Error(t, err)
+		assert.False(t, reloaded)
+
+		current, err := certificates.TLSConfig().GetCertificate(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, first.Certificate, current.Certificate)
+	})
+	t.Run("nok - missing file", func(t *testing.T) {
+		_, err := NewCertificates(TLSConfig{
+			CertFile: filepath.Join(t.TempDir(), "server.crt"),
+			KeyFile:  filepath.Join(t.TempDir(), "server.key"),
+		})
+
+		assert.Error(t, err)
+	})
+	t.Run("nok - empty client CA bundle", func(t *testing.T) {
+		dir := t.TempDir()
+		writeCertificate(t, dir, "server", nil)
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "ca.crt"), nil, 0o600))
+
+		_, err := NewCertificates(TLSConfig{
+			CertFile:     filepath.Join(dir, "server.crt"),
+			KeyFile:      filepath.Join(dir, "server.key"),
+			ClientCAFile: filepath.Join(dir, "ca.crt"),
+		})
+
+		assert.Error(t, err)
+	})
+}
+
+func Test_Certificates_Serve(t *testing.T) {
+	dir := t.TempDir()
+	ca := writeCertificate(t, dir, "ca", nil)
+	server := writeCertificate(t, dir, "server", &ca)
+	client := writeCertificate(t, dir, "client", &ca)
+
+	certificates, err := NewCertificates(TLSConfig{
+		CertFile:     filepath.Join(dir, "server.crt"),
+		KeyFile:      filepath.Join(dir, "server.key"),
+		MinVersion:   "1.2",
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+		ClientAuth:   "require",
+	})
+	assert.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.EnableHTTP2 = true
+	srv.TLS = certificates.TLSConfig()
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Leaf)
+
+	get := func(certificates []tls.Certificate) (*http.Response, error) {
+		httpClient := &http.Client{Transport: &http.Transport{
+			ForceAttemptHTTP2: true,
+			TLSClientConfig: &tls.Config{
+				RootCAs:      roots,
+				Certificates: certificates,
+				ServerName:   "localhost",
+			},
+		}}
+		return httpClient.Get(srv.URL)
+	}
+
+	t.Run("ok - HTTP/2 with a client certificate", func(t *testing.T) {
+		resp, err := get([]tls.Certificate{client})
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 2, resp.ProtoMajor)
+		assert.Equal(t, server.Certificate[0], resp.TLS.PeerCertificates[0].Raw)
+	})
+	t.Run("nok - no client certificate", func(t *testing.T) {
+		_, err := get(nil)
+
+		assert.Error(t, err)
+	})
+}
+
+func Test_TLSConfig_Validate(t *testing.T) {
+	t.Run("ok - disabled", func(t *testing.T) {
+		cfg := TLSConfig{}
+
+		assert.NoError(t, cfg.Validate())
+		assert.False(t, cfg.Enabled())
+	})
+	t.Run("ok - cipher suites", func(t *testing.T) {
+		cfg := TLSConfig{
+			CertFile:     "tls.crt",
+			KeyFile:      "tls.key",
+			CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+		}
+
+		assert.NoError(t, cfg.Validate())
+		assert.True(t, cfg.Enabled())
+	})
+	t.Run("nok - certificate without key", func(t *testing.T) {
+		cfg := TLSConfig{CertFile: "tls.crt"}
+
+		assert.Error(t, cfg.Validate())
+	})
+	t.Run("nok - client CA without certificate", func(t *testing.T) {
+		cfg := TLSConfig{ClientCAFile: "ca.crt"}
+
+		assert.Error(t, cfg.Validate())
+	})
+	t.Run("nok - insecure cipher suite", func(t *testing.T) {
+		cfg := TLSConfig{
+			CertFile:     "tls.crt",
+			KeyFile:      "tls.key",
+			CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"},
+		}
+
+		assert.Error(t, cfg.Validate())
+	})
+}
@@ -0,0 +1,107 @@
+package pkg_ratelimit
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests per Period, written as 100/1m. Bursts of up to Requests
+// are allowed as long as the average rate is respected.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+func (l *Limit) UnmarshalText(text []byte) error {
+	requests, period, ok := strings.Cut(string(text), "/")
+	if !ok {
+		return fmt.Errorf("invalid rate limit %q, expected requests/period", text)
+	}
+
+	var err error
+	if l.Requests, err = strconv.Atoi(strings.TrimSpace(requests)); err != nil || l.Requests <= 0 {
+		return fmt.Errorf("invalid rate limit %q, requests must be a positive integer", text)
+	}
+	if l.Period, err = time.ParseDuration(strings.TrimSpace(period)); err != nil || l.Period <= 0 {
+		return fmt.Errorf("invalid rate limit %q, period must be a positive duration", text)
+	}
+
+	return nil
+}
+
+func (l Limit) String() string {
+	if l.Requests == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%d/%s", l.Requests, l.Period)
+}
+
+// Limits maps route groups or identities to their limit, written as
+// examples=100/1m,api-keys=10/1m.
+type Limits map[string]Limit
+
+func (l *Limits) UnmarshalText(text []byte) error {
+	limits := make(Limits)
+
+	for _, entry := range strings.Split(string(text), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("invalid rate limit entry %q, expected name=requests/period", entry)
+		}
+
+		var limit Limit
+		if err := limit.UnmarshalText([]byte(value)); err != nil {
+			return err
+		}
+		limits[strings.TrimSpace(name)] = limit
+	}
+
+	*l = limits
+
+	return nil
+}
+
+func (l Limits) String() string {
+	names := make([]string, 0, len(l))
+	for name := range l {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]string, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, name+"="+l[name].String())
+	}
+
+	return strings.Join(entries, ",")
+}
+
+type RateLimitConfig struct {
+	Enabled bool  `env:"RATE_LIMIT_ENABLED" envDefault:"true"`
+	Default Limit `env:"RATE_LIMIT_DEFAULT" envDefault:"600/1m"`
+	// Groups overrides the default limit of route groups
+	Groups Limits `env:"RATE_LIMIT_GROUPS"`
+	// Identities overrides the limit of callers, identified by their subject or IP
+	Identities Limits `env:"RATE_LIMIT_IDENTITIES"`
+}
+
+// limitFor returns the limit applying to identity on group.
+func (cfg *RateLimitConfig) limitFor(group string, identity string) Limit {
+	if limit, ok := cfg.Identities[identity]; ok {
+		return limit
+	}
+	if limit, ok := cfg.Groups[group]; ok {
+		return limit
+	}
+
+	return cfg.Default
+}
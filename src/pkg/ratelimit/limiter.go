@@ -0,0 +1,66 @@
+package pkg_ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	pkg_cache "github.com/teyz/go-svc-template/pkg/cache"
+)
+
+// Limiter enforces limits shared by every instance through the cache, and falls
+// back to limits local to the instance while the cache is unavailable.
+type Limiter struct {
+	cache    pkg_cache.Cache
+	memory   *memoryStore
+	config   atomic.Pointer[RateLimitConfig]
+	degraded atomic.Bool
+}
+
+func NewLimiter(cfg *RateLimitConfig, cache pkg_cache.Cache) *Limiter {
+	l := &Limiter{
+		cache:  cache,
+		memory: newMemoryStore(),
+	}
+	l.config.Store(cfg)
+
+	return l
+}
+
+// SetConfig changes the limits, it is safe to call while serving.
+func (l *Limiter) SetConfig(cfg *RateLimitConfig) {
+	l.config.Store(cfg)
+}
+
+// Allow counts a request of identity on group and returns the applied limit
+// along with whether the request is allowed.
+func (l *Limiter) Allow(ctx context.Context, group string, identity string) (Limit, *pkg_cache.RateLimitResult) {
+	cfg := l.config.Load()
+	limit := cfg.limitFor(group, identity)
+	key := generateRateLimitCacheKey(group, identity)
+
+	if l.cache != nil {
+		result, err := l.cache.AllowRate(ctx, key, limit.Requests, limit.Period, limit.Requests)
+		if err == nil {
+			if l.degraded.Swap(false) {
+				log.Info().
+					Msg("pkg_ratelimit.Limiter.Allow: cache is available again, limits are shared")
+			}
+			return limit, result
+		}
+
+		if !l.degraded.Swap(true) {
+			log.Warn().Err(err).
+				Msg("pkg_ratelimit.Limiter.Allow: cache is unavailable, falling back to limits local to the instance")
+		}
+	}
+
+	return limit, l.memory.allowRate(key, limit.Requests, limit.Period, limit.Requests, time.Now())
+}
+
+func generateRateLimitCacheKey(group string, identity string) string {
+	return fmt.Sprintf("go-svc-template:ratelimit:%v:%v", group, identity)
+}
@@ -0,0 +1,165 @@
+package pkg_ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	pkg_auth "github.com/teyz/go-svc-template/pkg/auth"
+	pkg_cache "github.com/teyz/go-svc-template/pkg/cache"
+	cache_mocks "github.com/teyz/go-svc-template/pkg/cache/mocks"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Limits(t *testing.T) {
+	t.Run("ok - parse limits", func(t *testing.T) {
+		var limits Limits
+		assert.NoError(t, limits.UnmarshalText([]byte("examples=100/1m, api-keys=10/1s")))
+
+		assert.Equal(t, Limit{Requests: 100, Period: time.Minute}, limits["examples"])
+		assert.Equal(t, Limit{Requests: 10, Period: time.Second}, limits["api-keys"])
+		assert.Equal(t, "api-keys=10/1s,examples=100/1m0s", limits.String())
+	})
+	t.Run("nok - parse invalid limits", func(t *testing.T) {
+		var limits Limits
+		assert.Error(t, limits.UnmarshalText([]byte("examples")))
+		assert.Error(t, limits.UnmarshalText([]byte("examples=0/1m")))
+		assert.Error(t, limits.UnmarshalText([]byte("examples=10/forever")))
+	})
+	t.Run("ok - identity overrides group and default", func(t *testing.T) {
+		cfg := &RateLimitConfig{
+			Default:    Limit{Requests: 1, Period: time.Second},
+			Groups:     Limits{"examples": {Requests: 2, Period: time.Second}},
+			Identities: Limits{"akey_batch": {Requests: 3, Period: time.Second}},
+		}
+
+		assert.Equal(t, 1, cfg.limitFor("api-keys", "user").Requests)
+		assert.Equal(t, 2, cfg.limitFor("examples", "user").Requests)
+		assert.Equal(t, 3, cfg.limitFor("examples", "akey_batch").Requests)
+	})
+}
+
+func Test_MemoryStore(t *testing.T) {
+	m := newMemoryStore()
+	now := time.Now()
+
+	for i := 2; i >= 0; i-- {
+		result := m.allowRate("key", 3, time.Second, 3, now)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	result := m.allowRate("key", 3, time.Second, 3, now)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second/3, result.RetryAfter)
+
+	result = m.allowRate("key", 3, time.Second, 3, now.Add(time.Second/3))
+	assert.True(t, result.Allowed)
+
+	result = m.allowRate("other", 3, time.Second, 3, now)
+	assert.True(t, result.Allowed)
+}
+
+func serve(limiter *Limiter, subject string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, Middleware(limiter, "examples"))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if subject != "" {
+		claims := &pkg_auth.Claims{}
+		claims.Subject = subject
+		req = req.WithContext(pkg_auth.WithClaims(req.Context(), claims))
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func Test_Middleware(t *testing.T) {
+	cfg := &RateLimitConfig{
+		Enabled: true,
+		Default: Limit{Requests: 1, Period: time.Minute},
+	}
+
+	t.Run("ok - allowed by the cache", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		mock_cache.EXPECT().AllowRate(gomock.Any(), "go-svc-template:ratelimit:examples:user-1", 1, time.Minute, 1).Return(&pkg_cache.RateLimitResult{
+			Allowed:    true,
+			ResetAfter: time.Minute,
+		}, nil)
+
+		rec := serve(NewLimiter(cfg, mock_cache), "user-1")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "1", rec.Header().Get(HeaderRateLimitLimit))
+		assert.Equal(t, "0", rec.Header().Get(HeaderRateLimitRemaining))
+		assert.Equal(t, "60", rec.Header().Get(HeaderRateLimitReset))
+	})
+	t.Run("nok - denied by the cache", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		mock_cache.EXPECT().AllowRate(gomock.Any(), gomock.Any(), 1, time.Minute, 1).Return(&pkg_cache.RateLimitResult{
+			Allowed:    false,
+			RetryAfter: 1500 * time.Millisecond,
+			ResetAfter: time.Minute,
+		}, nil)
+
+		rec := serve(NewLimiter(cfg, mock_cache), "user-1")
+
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get(HeaderRetryAfter))
+
+		var resp pkg_http.HTTPResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.True(t, resp.Status.Error)
+		assert.Equal(t, http.StatusTooManyRequests, resp.Status.Code)
+	})
+	t.Run("ok - fall back to memory when the cache is unavailable", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		mock_cache.EXPECT().AllowRate(gomock.Any(), gomock.Any(), 1, time.Minute, 1).Return(nil, errors.New("connection refused")).Times(2)
+
+		limiter := NewLimiter(cfg, mock_cache)
+
+		assert.Equal(t, http.StatusOK, serve(limiter, "user-1").Code)
+		assert.Equal(t, http.StatusTooManyRequests, serve(limiter, "user-1").Code)
+	})
+	t.Run("ok - disabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mock_cache := cache_mocks.NewMockCache(ctrl)
+
+		rec := serve(NewLimiter(&RateLimitConfig{}, mock_cache), "user-1")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get(HeaderRateLimitLimit))
+	})
+}
+
+func Test_Identity(t *testing.T) {
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set(echo.HeaderXRealIP, "10.0.0.2")
+	assert.Equal(t, "10.0.0.1", Identity(e.NewContext(req, httptest.NewRecorder())))
+
+	claims := &pkg_auth.Claims{}
+	claims.Subject = "akey_batch"
+	req = req.WithContext(pkg_auth.WithClaims(context.Background(), claims))
+	assert.Equal(t, "akey_batch", Identity(e.NewContext(req, httptest.NewRecorder())))
+}
@@ -0,0 +1,69 @@
+package pkg_ratelimit
+
+import (
+	"sync"
+	"time"
+
+	pkg_cache "github.com/teyz/go-svc-template/pkg/cache"
+)
+
+// memoryStore applies the same algorithm as the Redis script within the process,
+// it is used while Redis is unavailable.
+type memoryStore struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	lastSweep time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		tats: make(map[string]time.Time),
+	}
+}
+
+func (m *memoryStore) allowRate(key string, rate int, period time.Duration, burst int, now time.Time) *pkg_cache.RateLimitResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(now)
+
+	emissionInterval := period / time.Duration(rate)
+	burstOffset := emissionInterval * time.Duration(burst)
+
+	tat, ok := m.tats[key]
+	if !ok || tat.Before(now) {
+		tat = now
+	}
+
+	newTat := tat.Add(emissionInterval)
+	diff := now.Sub(newTat.Add(-burstOffset))
+	if diff < 0 {
+		return &pkg_cache.RateLimitResult{
+			Allowed:    false,
+			RetryAfter: -diff,
+			ResetAfter: tat.Sub(now),
+		}
+	}
+
+	m.tats[key] = newTat
+
+	return &pkg_cache.RateLimitResult{
+		Allowed:    true,
+		Remaining:  int(diff / emissionInterval),
+		ResetAfter: newTat.Sub(now),
+	}
+}
+
+// sweep drops the keys whose quota is fully replenished, at most once a minute.
+func (m *memoryStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < time.Minute {
+		return
+	}
+	m.lastSweep = now
+
+	for key, tat := range m.tats {
+		if tat.Before(now) {
+			delete(m.tats, key)
+		}
+	}
+}
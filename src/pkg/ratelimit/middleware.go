@@ -0,0 +1,63 @@
+package pkg_ratelimit
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	pkg_auth "github.com/teyz/go-svc-template/pkg/auth"
+	pkg_errors "github.com/teyz/go-svc-template/pkg/errors"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRetryAfter         = "Retry-After"
+)
+
+// Middleware limits the requests of every caller on group. Callers are identified
+// by their subject once authenticated, by their IP otherwise, so it must run after
+// the authentication middleware.
+func Middleware(limiter *Limiter, group string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+
+			if !limiter.config.Load().Enabled {
+				return next(c)
+			}
+
+			limit, result := limiter.Allow(ctx, group, Identity(c))
+
+			header := c.Response().Header()
+			header.Set(HeaderRateLimitLimit, strconv.Itoa(limit.Requests))
+			header.Set(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+			header.Set(HeaderRateLimitReset, strconv.Itoa(seconds(result.ResetAfter)))
+
+			if !result.Allowed {
+				header.Set(HeaderRetryAfter, strconv.Itoa(seconds(result.RetryAfter)))
+				return c.JSON(pkg_http.TranslateError(ctx, pkg_errors.NewTooManyRequestsError(pkg_http.MessageTooManyRequestsError)))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// Identity returns the subject of the authenticated caller, or its IP.
+func Identity(c echo.Context) string {
+	if claims, ok := pkg_auth.ClaimsFromContext(c.Request().Context()); ok && claims.Subject != "" {
+		return claims.Subject
+	}
+
+	return c.RealIP()
+}
+
+// seconds rounds d up, as headers are in whole seconds and clients must not retry too early.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,22 @@
+package pkg_tenant
+
+import "fmt"
+
+type TenancyConfig struct {
+	// Enabled requires every private request to resolve a tenant, all of them use
+	// the default tenant otherwise
+	Enabled bool   `env:"TENANCY_ENABLED" envDefault:"false" reload:"false"`
+	Header  string `env:"TENANCY_HEADER" envDefault:"X-Tenant-ID"`
+	// PublicTenant is the only tenant served by the unauthenticated public
+	// endpoints, which never read the tenant from the request
+	PublicTenant string `env:"TENANCY_PUBLIC_TENANT" envDefault:"default" reload:"false"`
+}
+
+// Validate checks that the public tenant is a well formed tenant ID.
+func (cfg *TenancyConfig) Validate() error {
+	if !IsValid(cfg.PublicTenant) {
+		return fmt.Errorf("env: invalid TENANCY_PUBLIC_TENANT %q", cfg.PublicTenant)
+	}
+
+	return nil
+}
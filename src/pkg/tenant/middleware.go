@@ -0,0 +1,73 @@
+package pkg_tenant
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	pkg_auth "github.com/teyz/go-svc-template/pkg/auth"
+	pkg_http "github.com/teyz/go-svc-template/pkg/http"
+)
+
+// ScopeCrossTenant lets authenticated callers not bound to a tenant select one
+// with the tenant header, e.g. operators working on behalf of every tenant.
+const ScopeCrossTenant = "tenants:cross"
+
+// Middleware scopes requests to their tenant, read from the tenant_id claim of the
+// caller. Authenticated callers whose credentials carry no tenant are rejected,
+// unless granted ScopeCrossTenant to select it with the tenant header, which is
+// also read when authentication is disabled. It must run after the
+// authentication middleware.
+func Middleware(cfg *TenancyConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !cfg.Enabled {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+
+			tenant := c.Request().Header.Get(cfg.Header)
+			if claims, ok := pkg_auth.ClaimsFromContext(ctx); ok {
+				switch {
+				case claims.TenantID != "":
+					tenant = claims.TenantID
+				case !claims.HasScope(ScopeCrossTenant):
+					log.Warn().
+						Str("subject", claims.Subject).
+						Msg("pkg_tenant.Middleware: caller is not bound to a tenant")
+					return c.JSON(http.StatusForbidden, pkg_http.NewHTTPResponse(http.StatusForbidden, pkg_http.MessageForbidenError, nil))
+				}
+			}
+
+			if !IsValid(tenant) {
+				log.Warn().
+					Str("tenant", tenant).
+					Msg("pkg_tenant.Middleware: missing or invalid tenant")
+				return c.JSON(http.StatusBadRequest, pkg_http.NewHTTPResponse(http.StatusBadRequest, pkg_http.MessageBadRequestError, nil))
+			}
+
+			c.SetRequest(c.Request().WithContext(WithTenant(ctx, tenant)))
+
+			return next(c)
+		}
+	}
+}
+
+// PublicMiddleware scopes unauthenticated requests to the public tenant, so that
+// callers cannot read the data of another tenant and cached responses are the
+// same for everyone.
+func PublicMiddleware(cfg *TenancyConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !cfg.Enabled {
+				return next(c)
+			}
+
+			c.SetRequest(c.Request().WithContext(WithTenant(c.Request().Context(), cfg.PublicTenant)))
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package pkg_tenant
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	pkg_auth "github.com/teyz/go-svc-template/pkg/auth"
+)
+
+func serve(cfg *TenancyConfig, claims *pkg_auth.Claims, header string) (int, string) {
+	var tenant string
+
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error {
+		tenant = FromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	}, Middleware(cfg))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if claims != nil {
+		req = req.WithContext(pkg_auth.WithClaims(req.Context(), claims))
+	}
+	if header != "" {
+		req.Header.Set(cfg.Header, header)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec.Code, tenant
+}
+
+func Test_Middleware(t *testing.T) {
+	cfg := &TenancyConfig{
+		Enabled: true,
+		Header:  "X-Tenant-ID",
+	}
+
+	t.Run("ok - tenant from claim", func(t *testing.T) {
+		code, tenant := serve(cfg, &pkg_auth.Claims{TenantID: "acme"}, "")
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "acme", tenant)
+	})
+	t.Run("ok - claim wins over header", func(t *testing.T) {
+		code, tenant := serve(cfg, &pkg_auth.Claims{TenantID: "acme"}, "globex")
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "acme", tenant)
+	})
+	t.Run("ok - tenant from header with cross tenant scope", func(t *testing.T) {
+		code, tenant := serve(cfg, &pkg_auth.Claims{Scope: "examples:read " + ScopeCrossTenant}, "globex")
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "globex", tenant)
+	})
+	t.Run("ok - tenant from header when unauthenticated", func(t *testing.T) {
+		code, tenant := serve(cfg, nil, "globex")
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "globex", tenant)
+	})
+	t.Run("nok - authenticated without tenant and header", func(t *testing.T) {
+		code, tenant := serve(cfg, &pkg_auth.Claims{Scope: "examples:read"}, "globex")
+
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Empty(t, tenant)
+	})
+	t.Run("nok - missing tenant", func(t *testing.T) {
+		code, _ := serve(cfg, nil, "")
+
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+	t.Run("nok - invalid tenant", func(t *testing.T) {
+		code, _ := serve(cfg, nil, "acme:example:id")
+
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+	t.Run("ok - default tenant when disabled", func(t *testing.T) {
+		code, tenant := serve(&TenancyConfig{Header: "X-Tenant-ID"}, nil, "globex")
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, Default, tenant)
+	})
+}
+
+func Test_PublicMiddleware(t *testing.T) {
+	serve := func(cfg *TenancyConfig, header string) string {
+		var tenant string
+
+		e := echo.New()
+		e.GET("/", func(c echo.Context) error {
+			tenant = FromContext(c.Request().Context())
+			return c.NoContent(http.StatusOK)
+		}, PublicMiddleware(cfg))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(cfg.Header, header)
+		e.ServeHTTP(httptest.NewRecorder(), req)
+
+		return tenant
+	}
+
+	t.Run("ok - public tenant whatever the header", func(t *testing.T) {
+		tenant := serve(&TenancyConfig{Enabled: true, Header: "X-Tenant-ID", PublicTenant: "acme"}, "globex")
+
+		assert.Equal(t, "acme", tenant)
+	})
+	t.Run("ok - default tenant when disabled", func(t *testing.T) {
+		tenant := serve(&TenancyConfig{Header: "X-Tenant-ID", PublicTenant: "acme"}, "globex")
+
+		assert.Equal(t, Default, tenant)
+	})
+}
+
+func Test_TenancyConfig_Validate(t *testing.T) {
+	t.Run("ok - valid public tenant", func(t *testing.T) {
+		assert.NoError(t, (&TenancyConfig{PublicTenant: "default"}).Validate())
+	})
+	t.Run("nok - invalid public tenant", func(t *testing.T) {
+		assert.Error(t, (&TenancyConfig{PublicTenant: "acme:example"}).Validate())
+	})
+}
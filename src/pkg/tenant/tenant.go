@@ -0,0 +1,34 @@
+package pkg_tenant
+
+import (
+	"context"
+	"regexp"
+)
+
+// Default is the tenant of single-tenant deployments and of calls made outside
+// of a request, e.g. by the CLI.
+const Default = "default"
+
+// validID keeps tenant IDs safe to embed in cache keys.
+var validID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx scoped to tenant.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// FromContext returns the tenant ctx is scoped to, the default one when unset.
+func FromContext(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantKey{}).(string); ok && tenant != "" {
+		return tenant
+	}
+
+	return Default
+}
+
+// IsValid reports whether tenant is a well formed tenant ID.
+func IsValid(tenant string) bool {
+	return validID.MatchString(tenant)
+}